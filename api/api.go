@@ -1,12 +1,36 @@
 package api
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	// AnnotationSecretDataRedacted is set on downloaded secrets whose data values have been replaced by placeholders.
+	AnnotationSecretDataRedacted = "kcpcl.io/secret-data-redacted"
+	// AnnotationNodeTemplatePool is set on synthetic template nodes to the name of the worker pool they were derived from.
+	AnnotationNodeTemplatePool = "kcpcl.io/node-template-pool"
+	// AnnotationNodeTemplateMin is set on synthetic template nodes to the minimum number of nodes of the worker pool.
+	AnnotationNodeTemplateMin = "kcpcl.io/node-template-min"
+	// AnnotationNodeTemplateMax is set on synthetic template nodes to the maximum number of nodes of the worker pool.
+	AnnotationNodeTemplateMax = "kcpcl.io/node-template-max"
+	// AnnotationKWOKNode is set on uploaded nodes to AnnotationKWOKNodeValue so that they are managed by a KWOK
+	// controller configured with the KWOK configuration written on upload.
+	AnnotationKWOKNode      = "kwok.x-k8s.io/node"
+	AnnotationKWOKNodeValue = "fake"
+	// AnnotationOriginalNodeName is set on uploaded pods to the name of the node they were bound to in the source cluster.
+	AnnotationOriginalNodeName = "kcpcl.io/original-node-name"
 )
 
 var (
@@ -28,6 +52,8 @@ var (
 		"nodes",
 		"storage.k8s.io/v1/csinodes",
 		"pods"}
+	// DefaultClosureRootGVRs are the GVRs whose selected objects form the roots of a closure download.
+	DefaultClosureRootGVRs = []string{"pods"}
 )
 
 // CopierConfig represents input configuration for creating and initializing a ShootCopier
@@ -35,34 +61,485 @@ type CopierConfig struct {
 	// KubeConfigPath represents path to source or Target kubeconfig
 	KubeConfigPath string
 
+	// TargetKubeConfigPath represents path to the target kubeconfig when copying directly from the source cluster.
+	TargetKubeConfigPath string
+
+	// TargetShoot represents the coordinates of the target gardener shoot when copying from one shoot to another. It is
+	// only used if TargetKubeConfigPath is empty and is accessed using a short-lived admin kubeconfig.
+	TargetShoot ShootCoords
+
+	// ForceKubeSystem indicates whether a shoot-to-shoot copy may create objects in the kube-system namespace.
+	ForceKubeSystem bool
+
+	// DryRun indicates whether a shoot-to-shoot copy should only preview the objects it would copy.
+	DryRun bool
+
+	// GardenKubeConfigPath represents path to the kubeconfig of the gardener garden cluster.
+	GardenKubeConfigPath string
+
+	// Shoot represents the coordinates of the gardener shoot cluster.
+	Shoot ShootCoords
+
 	// ControlKubeConfigPath represents path to shoot control cluster kubeconfig.
 	ControlKubeConfigPath string
 
+	// ControlNamespace is the control-plane namespace of the shoot in the seed. Ex: shoot--dev--aw. Defaults to the
+	// namespace derived from the shoot coordinates.
+	ControlNamespace string
+
 	// GVRStrings represent list of GVR to download/upload int the form: '[group/][version/]resource. Ex: pods nodes
 	GVRStrings []string
 
+	// PoolSize is the number of concurrent workers.
 	PoolSize   int
 	OrderKinds bool
+
+	// QPS and Burst rate limit the requests of the kube clients. They default to 1.5 times and once the PoolSize.
+	QPS   float32
+	Burst int
+
+	// AdaptiveConcurrency indicates whether the number of concurrent requests should be reduced on throttling and
+	// timeouts and increased again while latency is healthy, up to PoolSize.
+	AdaptiveConcurrency bool
+
+	// IncludeSecretData indicates whether secret values should be downloaded as-is. By default, secret values are
+	// redacted with placeholders of the same length.
+	IncludeSecretData bool
+
+	// DummySecretData indicates whether secret values should be regenerated with random dummy values on upload.
+	DummySecretData bool
+
+	// Incremental indicates whether download should only rewrite objects whose resourceVersion changed since the
+	// previous download into the same obj dir and delete the objects that no longer exist.
+	Incremental bool
+
+	// NodeTemplates indicates whether download should also generate synthetic template nodes for the worker pools of
+	// the shoot.
+	NodeTemplates bool
+
+	// KWOK indicates whether uploaded nodes should be annotated to be managed by a KWOK controller.
+	KWOK bool
+
+	// UploadReportPath is the path where upload writes the JSON report of the outcome of every object. Empty disables
+	// writing the report.
+	UploadReportPath string
+
+	// MaxUploadFailures is the number of objects that may fail to upload before upload fails.
+	MaxUploadFailures int
+
+	// UploadPolicy determines how uploads handle objects that already exist or whose creation is forbidden.
+	UploadPolicy UploadPolicy
+
+	// Retry determines how list and get requests are retried on transient errors. Create requests of uploads are
+	// only retried when they were certainly not processed, ex: after 429 or a refused connection.
+	Retry RetryPolicy
+
+	// RecordEvents indicates whether watch should append the observed object events to the event log in the obj dir.
+	RecordEvents bool
+}
+
+// ClientRateLimit returns the QPS and burst of the kube clients, defaulting them based on the PoolSize.
+func (c CopierConfig) ClientRateLimit() (qps float32, burst int) {
+	qps, burst = c.QPS, c.Burst
+	if qps <= 0 {
+		qps = float32(c.PoolSize) * 1.5
+	}
+	if burst <= 0 {
+		burst = c.PoolSize
+	}
+	return
 }
 
 // ShootCoords represents the coordinates of a gardner shoot cluster. It can be used to represent both the shoot and seed.
 type ShootCoords struct {
-	Landscape string
-	Project   string
-	Name      string
+	Landscape string `json:"landscape"`
+	Project   string `json:"project"`
+	Name      string `json:"name"`
 }
 
 func (s ShootCoords) String() string {
 	return fmt.Sprintf("(%s|%s|%s)", s.Landscape, s.Project, s.Name)
 }
 
+// ShootInfo represents a gardener shoot cluster resolved via the garden cluster.
 type ShootInfo struct {
-	Coords    ShootCoords
+	Coords ShootCoords
+	// Namespace is the control-plane namespace of the shoot in its seed. Ex: shoot--dev--aw
 	Namespace string
+	// Seed represents the coordinates of the seed hosting the control-plane of the shoot. It is empty if the seed is not
+	// a managed seed or cannot be read.
+	Seed ShootCoords
+}
+
+// ObjSelector selects the objects of a resource by namespace, labels and fields.
+type ObjSelector struct {
+	// Namespace restricts selection to the given namespace. Empty selects across all namespaces.
+	Namespace string
+	// LabelSelector is a label selector in the form accepted by kubectl. Ex: app=nginx,tier!=frontend
+	LabelSelector string
+	// FieldSelector is a field selector in the form accepted by kubectl. Ex: status.phase=Pending
+	FieldSelector string
+}
+
+// ObjEvent represents a change to an object observed while watching a cluster.
+type ObjEvent struct {
+	Time time.Time       `json:"time"`
+	Type watch.EventType `json:"type"`
+	// GVR is the GVR of the object in the form accepted by ParseGVR.
+	GVR    string                     `json:"gvr"`
+	Object *unstructured.Unstructured `json:"object"`
+}
+
+// DownloadManifest records the state of a download into an obj dir.
+type DownloadManifest struct {
+	Time time.Time `json:"time"`
+	// Shoot holds the coordinates of the shoot when the download was done via gardener shoot coordinates.
+	Shoot *ShootCoords `json:"shoot,omitempty"`
+	// Seed holds the coordinates of the seed of the shoot when the download was done via gardener shoot coordinates and
+	// the seed could be resolved.
+	Seed *ShootCoords `json:"seed,omitempty"`
+	// Resources holds the manifest of each downloaded resource keyed by the GVR in the form accepted by ParseGVR.
+	Resources map[string]ResourceManifest `json:"resources"`
+	// NumRetries is the number of list requests of the download retried after transient errors.
+	NumRetries int `json:"numRetries,omitempty"`
+}
+
+// ResourceManifest records the state of the downloaded objects of a single GVR.
+type ResourceManifest struct {
+	// ObjResourceVersions holds the resourceVersion of each object keyed by its file name within the resource dir.
+	ObjResourceVersions map[string]string `json:"objResourceVersions"`
+}
+
+// DownloadChangelog records the objects that changed between two incremental downloads. Objects are identified by
+// their file paths relative to the obj dir.
+type DownloadChangelog struct {
+	Time         time.Time `json:"time"`
+	PreviousTime time.Time `json:"previousTime"`
+	Added        []string  `json:"added,omitempty"`
+	Modified     []string  `json:"modified,omitempty"`
+	Deleted      []string  `json:"deleted,omitempty"`
+}
+
+// ObjID identifies an object by its GVR in the form accepted by ParseGVR, namespace and name.
+type ObjID struct {
+	GVR       string `json:"gvr"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (o ObjID) String() string {
+	if o.Namespace == "" {
+		return o.GVR + " " + o.Name
+	}
+	return o.GVR + " " + o.Namespace + "/" + o.Name
+}
+
+// FieldDiff represents a difference of a single field between two versions of an object. Old is nil if the field was
+// added and New is nil if the field was removed.
+type FieldDiff struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// ObjDiff represents the field differences between two versions of an object.
+type ObjDiff struct {
+	ID     ObjID       `json:"id"`
+	Fields []FieldDiff `json:"fields"`
+}
+
+// DiffReport represents the differences between two sets of objects A and B.
+type DiffReport struct {
+	// Added holds objects present in B but not in A.
+	Added []ObjID `json:"added,omitempty"`
+	// Removed holds objects present in A but not in B.
+	Removed []ObjID `json:"removed,omitempty"`
+	// Changed holds objects present in both A and B with different fields.
+	Changed      []ObjDiff `json:"changed,omitempty"`
+	NumUnchanged int       `json:"numUnchanged"`
+}
+
+// ShootCopyReport lists the objects copied, or to be copied in a dry-run, from one shoot to another.
+type ShootCopyReport struct {
+	Source  ShootCoords  `json:"source"`
+	Target  ShootCoords  `json:"target"`
+	DryRun  bool         `json:"dryRun"`
+	Copied  []ObjID      `json:"copied,omitempty"`
+	Skipped []SkippedObj `json:"skipped,omitempty"`
+}
+
+// SkippedObj is an object skipped by a shoot-to-shoot copy along with the reason.
+type SkippedObj struct {
+	ID     ObjID  `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// SchedulingReport summarizes the scheduling of the pods uploaded from an obj dir.
+type SchedulingReport struct {
+	NumPods          int  `json:"numPods"`
+	NumBound         int  `json:"numBound"`
+	NumUnschedulable int  `json:"numUnschedulable"`
+	NumPending       int  `json:"numPending"`
+	TimedOut         bool `json:"timedOut"`
+	// Unschedulable groups the unschedulable pods by the reasons reported in their PodScheduled condition.
+	Unschedulable []UnschedulableReason `json:"unschedulable,omitempty"`
+	// TimeToSchedule holds the percentiles of the durations between creation and binding of the bound pods.
+	TimeToSchedule DurationPercentiles `json:"timeToSchedule"`
+}
+
+// UnschedulableReason is a reason reported by the scheduler along with the pods it applies to.
+type UnschedulableReason struct {
+	Reason string `json:"reason"`
+	// Pods are the pods in the form namespace/name.
+	Pods []string `json:"pods"`
+}
+
+// DurationPercentiles holds percentiles of a set of durations.
+type DurationPercentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// UploadOutcome is the outcome of uploading a single object.
+type UploadOutcome string
+
+const (
+	UploadCreated       UploadOutcome = "created"
+	UploadUpdated       UploadOutcome = "updated"
+	UploadReplaced      UploadOutcome = "replaced"
+	UploadSkippedExists UploadOutcome = "skipped-exists"
+	UploadForbidden     UploadOutcome = "forbidden"
+	UploadFailed        UploadOutcome = "failed"
+)
+
+// ObjUploadResult is the outcome of uploading a single object along with the latency of its requests. Error holds the
+// cause of skipped outcomes as well.
+type ObjUploadResult struct {
+	ID      ObjID         `json:"id"`
+	Kind    string        `json:"kind"`
+	Outcome UploadOutcome `json:"outcome"`
+	Error   string        `json:"error,omitempty"`
+	Latency time.Duration `json:"latency"`
+	// Retries is the number of times the create request was retried after it was throttled or refused.
+	Retries int `json:"retries,omitempty"`
+}
+
+// KindUploadSummary counts the upload outcomes of the objects of a kind.
+type KindUploadSummary struct {
+	Kind             string              `json:"kind"`
+	NumCreated       int                 `json:"numCreated"`
+	NumUpdated       int                 `json:"numUpdated"`
+	NumReplaced      int                 `json:"numReplaced"`
+	NumSkippedExists int                 `json:"numSkippedExists"`
+	NumForbidden     int                 `json:"numForbidden"`
+	NumFailed        int                 `json:"numFailed"`
+	NumRetries       int                 `json:"numRetries"`
+	Latency          DurationPercentiles `json:"latency"`
+}
+
+// ConcurrencyStats summarizes how the number of concurrent requests was adapted.
+type ConcurrencyStats struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Final int `json:"final"`
+	// NumDecreases is the number of times the concurrency was reduced after throttling or timeouts.
+	NumDecreases int `json:"numDecreases"`
+}
+
+// UploadReport lists the outcome of every object uploaded from an obj dir.
+type UploadReport struct {
+	NumObjs          int           `json:"numObjs"`
+	NumCreated       int           `json:"numCreated"`
+	NumUpdated       int           `json:"numUpdated"`
+	NumReplaced      int           `json:"numReplaced"`
+	NumSkippedExists int           `json:"numSkippedExists"`
+	NumForbidden     int           `json:"numForbidden"`
+	NumFailed        int           `json:"numFailed"`
+	NumRetries       int           `json:"numRetries"`
+	Duration         time.Duration `json:"duration"`
+	// Throughput is the effective number of objects uploaded per second.
+	Throughput float64 `json:"throughput"`
+	// Concurrency is only set when the concurrency was adapted during upload.
+	Concurrency *ConcurrencyStats   `json:"concurrency,omitempty"`
+	Kinds       []KindUploadSummary `json:"kinds"`
+	Objects     []ObjUploadResult   `json:"objects"`
+}
+
+// PlacementReport compares the original placement of pods on nodes in the source cluster against their placement
+// after being re-scheduled in a target cluster.
+type PlacementReport struct {
+	NumPods int `json:"numPods"`
+	// NumMoved is the number of pods bound to a different node than originally.
+	NumMoved int `json:"numMoved"`
+	// NumUnplaced is the number of originally bound pods which are not bound anymore.
+	NumUnplaced int `json:"numUnplaced"`
+	// NumNewlyPlaced is the number of originally unbound pods which are bound now.
+	NumNewlyPlaced int              `json:"numNewlyPlaced"`
+	Before         PlacementSummary `json:"before"`
+	After          PlacementSummary `json:"after"`
+	Nodes          []NodePlacement  `json:"nodes"`
+	Moved          []PodMove        `json:"moved,omitempty"`
+}
+
+// PlacementSummary summarizes the bin-packing of pods on nodes.
+type PlacementSummary struct {
+	NumNodesUsed int `json:"numNodesUsed"`
+	// NumNodesEmpty is the number of nodes which host no pods other than DaemonSet pods.
+	NumNodesEmpty int `json:"numNodesEmpty"`
+	// CPUEfficiency is the percentage of the allocatable CPU of used nodes that is requested.
+	CPUEfficiency float64 `json:"cpuEfficiency"`
+	// MemoryEfficiency is the percentage of the allocatable memory of used nodes that is requested.
+	MemoryEfficiency float64 `json:"memoryEfficiency"`
+}
+
+// NodePlacement represents the pods placed on a node before and after re-scheduling.
+type NodePlacement struct {
+	Name        string              `json:"name"`
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	Before      NodeUsage           `json:"before"`
+	After       NodeUsage           `json:"after"`
+}
+
+// NodeUsage represents the pods placed on a node and their resource requests.
+type NodeUsage struct {
+	NumPods           int                 `json:"numPods"`
+	Requests          corev1.ResourceList `json:"requests"`
+	CPUUtilization    float64             `json:"cpuUtilization"`
+	MemoryUtilization float64             `json:"memoryUtilization"`
+}
+
+// SimulationReport summarizes the in-process scheduling simulation of the pods of an obj dir.
+type SimulationReport struct {
+	NumPods          int                   `json:"numPods"`
+	NumScheduled     int                   `json:"numScheduled"`
+	NumUnschedulable int                   `json:"numUnschedulable"`
+	Unschedulable    []UnschedulableReason `json:"unschedulable,omitempty"`
+	// Placement compares the original placement of the pods against their simulated placement.
+	Placement PlacementReport `json:"placement"`
+}
+
+// SchedulerConfigOpts customizes the kube-scheduler configuration generated for a target cluster.
+type SchedulerConfigOpts struct {
+	// TemplatePath is the path of a user-supplied kube-scheduler configuration template used instead of the embedded
+	// template. The template is executed with KubeSchedulerTmplParams.
+	TemplatePath string
+	// ProfilePaths are the paths of KubeSchedulerProfile YAML fragments. A fragment replaces the profile of the same
+	// scheduler name or is appended as a new profile.
+	ProfilePaths []string
+	// ExtenderPaths are the paths of Extender YAML fragments appended to the extenders of the configuration.
+	ExtenderPaths []string
+	// PercentageOfNodesToScore is the percentage of feasible nodes scored per pod. Zero leaves the scheduler default.
+	PercentageOfNodesToScore int32
+	// Parallelism is the number of parallel scheduling workers. Zero leaves the scheduler default.
+	Parallelism int32
+	// PluginWeights are the score plugin weights set on the profiles.
+	PluginWeights []PluginWeight
+}
+
+// PluginWeight is the weight of a score plugin in a scheduler profile.
+type PluginWeight struct {
+	Profile string
+	Plugin  string
+	Weight  int32
+}
+
+// PodMove represents a pod bound to a different node after re-scheduling. An empty node name denotes an unbound pod.
+type PodMove struct {
+	Pod  string `json:"pod"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// InspectReport summarizes the objects in an obj dir.
+type InspectReport struct {
+	NumObjs int `json:"numObjs"`
+	// CountsByGVR holds the number of objects keyed by GVR in the form accepted by ParseGVR.
+	CountsByGVR map[string]int `json:"countsByGVR"`
+	// CountsByNamespace holds the number of namespaced objects keyed by namespace.
+	CountsByNamespace map[string]int     `json:"countsByNamespace"`
+	NodeGroups        []NodeGroupSummary `json:"nodeGroups"`
+	Pods              PodSummary         `json:"pods"`
+	// PriorityClassCounts holds the number of pods keyed by priority class name.
+	PriorityClassCounts map[string]int   `json:"priorityClassCounts"`
+	TopNamespaces       []NamespaceCount `json:"topNamespaces"`
+}
+
+// NodeGroupSummary summarizes the nodes of an instance type in a zone.
+type NodeGroupSummary struct {
+	InstanceType string              `json:"instanceType"`
+	Zone         string              `json:"zone"`
+	NumNodes     int                 `json:"numNodes"`
+	Capacity     corev1.ResourceList `json:"capacity"`
+	Allocatable  corev1.ResourceList `json:"allocatable"`
+}
+
+// PodSummary summarizes the scheduling state and resource requests of pods.
+type PodSummary struct {
+	NumPods    int `json:"numPods"`
+	NumBound   int `json:"numBound"`
+	NumPending int `json:"numPending"`
+	// Requests holds the sum of the resource requests of all non-terminated pods.
+	Requests corev1.ResourceList `json:"requests"`
+	// Allocatable holds the sum of the allocatable resources of all nodes.
+	Allocatable corev1.ResourceList `json:"allocatable"`
+}
+
+// NamespaceCount represents the number of pods in a namespace.
+type NamespaceCount struct {
+	Namespace string `json:"namespace"`
+	NumPods   int    `json:"numPods"`
 }
 
-// ShootCopier offers methods to copy k8s objects from gardener shoot data and control planes (seed) to a target cluster.
-// TODO: also offer way to copy from one shoot to another shoot later
+// Severity is the severity of a validation problem.
+type Severity string
+
+const (
+	// SeverityError indicates a problem that prevents objects from being uploaded or scheduled.
+	SeverityError Severity = "error"
+	// SeverityWarning indicates a problem that does not prevent upload or scheduling.
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationProblem represents a problem found while validating an obj dir.
+type ValidationProblem struct {
+	// Path is the path of the object file with the problem.
+	Path     string   `json:"path"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// ValidationReport represents the result of validating an obj dir.
+type ValidationReport struct {
+	NumObjs     int                 `json:"numObjs"`
+	NumErrors   int                 `json:"numErrors"`
+	NumWarnings int                 `json:"numWarnings"`
+	Problems    []ValidationProblem `json:"problems,omitempty"`
+}
+
+// OutputFormat is the format in which reports are written.
+type OutputFormat string
+
+const (
+	OutputText  OutputFormat = "text"
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+)
+
+// ReplayOpts represents options for replaying an event log against a target cluster.
+type ReplayOpts struct {
+	// Speed is the factor by which replay is faster than the original timing. Ex: 2 replays twice as fast.
+	Speed float64
+	// Start is the offset from the first event before which events are skipped.
+	Start time.Duration
+	// Stop is the offset from the first event after which replay stops. Zero replays till the end.
+	Stop time.Duration
+}
+
+// ShootCopier offers methods to copy k8s objects from gardener shoot data and control planes (seed) to a target cluster
+// or to another shoot.
 type ShootCopier interface {
 	GetConfig() CopierConfig
 
@@ -70,7 +547,49 @@ type ShootCopier interface {
 
 	DownloadObjects(ctx context.Context, baseObjDir string, gvrList []schema.GroupVersionResource) error
 
+	// DownloadClosure downloads the objects of rootGVRs matching the given selector along with all objects transitively
+	// referenced by them and all nodes and CSI nodes, so that the selected objects can be scheduled in a target cluster.
+	DownloadClosure(ctx context.Context, baseObjDir string, rootGVRs []schema.GroupVersionResource, selector ObjSelector) error
+
+	// CopyShootObjects copies the objects of the given GVRs from the source shoot to the target shoot of the config.
+	// Nodes, objects bound to nodes and, unless forced, objects in kube-system are skipped. Objects that already exist
+	// in the target are left untouched.
+	CopyShootObjects(ctx context.Context, gvrList []schema.GroupVersionResource) (ShootCopyReport, error)
+
+	// DownloadControlObjects downloads the machine and cluster-autoscaler objects of the control-plane namespace of the
+	// shoot from the seed into the control subtree of baseObjDir.
+	DownloadControlObjects(ctx context.Context, baseObjDir string) error
+
+	// GenNodeTemplates generates a synthetic template node for each worker pool and zone of the shoot into the node
+	// templates subtree of baseObjDir using the shoot spec from the garden cluster.
+	GenNodeTemplates(ctx context.Context, baseObjDir string) error
+
+	// UploadObjects uploads the objects of baseObjDir to the cluster. Upload fails once more than
+	// CopierConfig.MaxUploadFailures objects fail.
 	UploadObjects(ctx context.Context, baseObjDir string) error
+
+	// UploadObjectsWithReport is UploadObjects that also reports the outcome of every object.
+	UploadObjectsWithReport(ctx context.Context, baseObjDir string) (UploadReport, error)
+
+	// WaitScheduled watches the pods uploaded from baseObjDir until each is bound to a node or reported unschedulable
+	// or the timeout elapses.
+	WaitScheduled(ctx context.Context, baseObjDir string, timeout time.Duration) (SchedulingReport, error)
+
+	// SimulateLifecycle keeps the Ready condition and lease of every node in the cluster fresh and marks pods bound to
+	// nodes Running every interval until the context is cancelled.
+	SimulateLifecycle(ctx context.Context, interval time.Duration) error
+
+	// WatchObjects downloads the objects of the given GVRs and then keeps baseObjDir in sync with the source cluster
+	// until the context is cancelled.
+	WatchObjects(ctx context.Context, baseObjDir string, gvrList []schema.GroupVersionResource) error
+
+	// ReplayEvents applies the events in the event log of baseObjDir to the target cluster honouring their original
+	// timing scaled by the replay speed.
+	ReplayEvents(ctx context.Context, baseObjDir string, opts ReplayOpts) error
+
+	// CopyObjects copies the objects of the given GVRs from the source cluster directly to the target cluster configured
+	// via CopierConfig.TargetKubeConfigPath. If teeObjDir is not empty, the source objects are also saved there.
+	CopyObjects(ctx context.Context, gvrList []schema.GroupVersionResource, teeObjDir string) error
 }
 
 // ParseGVR parses strings like:  "pods" "apps/v1/deployments" "scheduling.k8s.io/v1/priorityclasses"
@@ -101,6 +620,14 @@ func ParseGVR(arg string) (gvr schema.GroupVersionResource, err error) {
 	return
 }
 
+// FormatGVR formats the given GVR in the form accepted by ParseGVR.
+func FormatGVR(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Version + "/" + gvr.Resource
+	}
+	return gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+}
+
 func ParseGVRs(args []string) (gvrList []schema.GroupVersionResource, err error) {
 	var gvr schema.GroupVersionResource
 	for _, arg := range args {
@@ -112,3 +639,127 @@ func ParseGVRs(args []string) (gvrList []schema.GroupVersionResource, err error)
 	}
 	return
 }
+
+// ParsePluginWeight parses strings like: "bin-packing-scheduler:NodeResourcesFit=5"
+func ParsePluginWeight(arg string) (pw PluginWeight, err error) {
+	profilePlugin, weight, ok := strings.Cut(arg, "=")
+	if !ok {
+		err = fmt.Errorf("%w: missing weight in plugin weight %q", ErrInvalidOpt, arg)
+		return
+	}
+	pw.Profile, pw.Plugin, ok = strings.Cut(profilePlugin, ":")
+	if !ok || pw.Profile == "" || pw.Plugin == "" {
+		err = fmt.Errorf("%w: expected <profile>:<plugin>=<weight> in plugin weight %q", ErrInvalidOpt, arg)
+		return
+	}
+	w, err := strconv.ParseInt(weight, 10, 32)
+	if err != nil || w <= 0 {
+		err = fmt.Errorf("%w: weight in plugin weight %q must be a positive integer", ErrInvalidOpt, arg)
+		return
+	}
+	pw.Weight = int32(w)
+	return
+}
+
+func ParsePluginWeights(args []string) (pluginWeights []PluginWeight, err error) {
+	var pw PluginWeight
+	for _, arg := range args {
+		pw, err = ParsePluginWeight(arg)
+		if err != nil {
+			return
+		}
+		pluginWeights = append(pluginWeights, pw)
+	}
+	return
+}
+
+// RetryPolicy determines how requests failing with transient errors, ex: timeouts, 429, 5xx and connection resets, are
+// retried with exponential backoff and jitter. A Retry-After delay suggested by the server takes precedence over a
+// shorter backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request including the first. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry. It doubles with every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff between retries.
+	MaxBackoff time.Duration
+}
+
+// ExistsPolicy is the action taken when an uploaded object already exists.
+type ExistsPolicy string
+
+const (
+	// ExistsSkip leaves the existing object as is.
+	ExistsSkip ExistsPolicy = "skip"
+	// ExistsFail fails the upload of the object.
+	ExistsFail ExistsPolicy = "fail"
+	// ExistsUpdate updates the existing object using server-side apply.
+	ExistsUpdate ExistsPolicy = "update"
+	// ExistsReplace deletes the existing object and creates it again.
+	ExistsReplace ExistsPolicy = "replace"
+)
+
+// ForbiddenPolicy is the action taken when the creation of an uploaded object, or the update or replace of an existing
+// one, is forbidden.
+type ForbiddenPolicy string
+
+const (
+	// ForbiddenSkip skips the object.
+	ForbiddenSkip ForbiddenPolicy = "skip"
+	// ForbiddenFail fails the upload of the object.
+	ForbiddenFail ForbiddenPolicy = "fail"
+)
+
+// UploadPolicy determines how uploads handle objects that already exist or whose creation is forbidden. Empty
+// policies default to skip.
+type UploadPolicy struct {
+	OnExists    ExistsPolicy
+	OnForbidden ForbiddenPolicy
+	// KindOnExists overrides OnExists for the kinds it holds.
+	KindOnExists map[string]ExistsPolicy
+	// KindOnForbidden overrides OnForbidden for the kinds it holds.
+	KindOnForbidden map[string]ForbiddenPolicy
+}
+
+// ExistsPolicyFor returns the exists policy of the given kind.
+func (p UploadPolicy) ExistsPolicyFor(kind string) ExistsPolicy {
+	return cmp.Or(p.KindOnExists[kind], p.OnExists, ExistsSkip)
+}
+
+// ForbiddenPolicyFor returns the forbidden policy of the given kind.
+func (p UploadPolicy) ForbiddenPolicyFor(kind string) ForbiddenPolicy {
+	return cmp.Or(p.KindOnForbidden[kind], p.OnForbidden, ForbiddenSkip)
+}
+
+// ParseUploadPolicy parses exists and forbidden policies in the form <policy> or <kind>=<policy>.
+// Ex: "update", "Secret=replace"
+func ParseUploadPolicy(onExists, onForbidden []string) (p UploadPolicy, err error) {
+	p.OnExists, p.KindOnExists, err = parseKindPolicies(onExists, ExistsSkip, ExistsFail, ExistsUpdate, ExistsReplace)
+	if err != nil {
+		return
+	}
+	p.OnForbidden, p.KindOnForbidden, err = parseKindPolicies(onForbidden, ForbiddenSkip, ForbiddenFail)
+	return
+}
+
+func parseKindPolicies[P ~string](args []string, valid ...P) (def P, byKind map[string]P, err error) {
+	for _, arg := range args {
+		kind, policy, ok := strings.Cut(arg, "=")
+		if !ok {
+			kind, policy = "", arg
+		}
+		if !slices.Contains(valid, P(policy)) {
+			err = fmt.Errorf("%w: policy %q in %q must be one of %v", ErrInvalidOpt, policy, arg, valid)
+			return
+		}
+		if kind == "" {
+			def = P(policy)
+			continue
+		}
+		if byKind == nil {
+			byKind = make(map[string]P)
+		}
+		byKind[kind] = P(policy)
+	}
+	return
+}
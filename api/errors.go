@@ -6,14 +6,19 @@ var (
 	ErrMissingKubeConfig        = errors.New("missing kubeconfig")
 	ErrMissingShootKubeConfig   = errors.New("missing shoot kubeconfig")
 	ErrMissingControlKubeConfig = errors.New("missing shoot control-plane kubeconfig")
+	ErrMissingTargetKubeConfig  = errors.New("missing target kubeconfig")
 	ErrObjDirNotExist           = errors.New("obj dir not exist")
 	ErrCantReadObjDir           = errors.New("cant read obj dir")
 	ErrMissingObjDir            = errors.New("missing obj dir")
 
-	ErrMissingGVRs      = errors.New("missing GVRs")
-	ErrMissingLandscape = errors.New("missing gardener landscape")
-	ErrMissingProject   = errors.New("missing gardener project")
-	ErrMissingShoot     = errors.New("missing gardener shoot")
+	ErrInvalidOpt          = errors.New("invalid option")
+	ErrInvalidOutputFormat = errors.New("invalid output format")
+
+	ErrMissingGVRs       = errors.New("missing GVRs")
+	ErrMissingLandscape  = errors.New("missing gardener landscape")
+	ErrMissingProject    = errors.New("missing gardener project")
+	ErrMissingShoot      = errors.New("missing gardener shoot")
+	ErrMissingReportType = errors.New("missing report type")
 
 	ErrInvalidGVR                = errors.New("invalid GVR format")
 	ErrNotFoundGVR               = errors.New("not found GVR")
@@ -21,14 +26,32 @@ var (
 	ErrGardenCtlConfigLoadFailed = errors.New("failed to load gardenctl config")
 	ErrCreateKubeClient          = errors.New("failed create kube client")
 	ErrCreateGardenClient        = errors.New("failed create garden client")
+	ErrGenKubeConfig             = errors.New("cannot generate shoot kubeconfig")
+	ErrResolveShoot              = errors.New("cannot resolve gardener shoot")
+	ErrGenNodeTemplates          = errors.New("cannot generate node templates")
 
 	ErrDiscovery = errors.New("cannot discover resources")
 
-	ErrLoadObj      = errors.New("cannot load object")
-	ErrLoadTemplate = errors.New("cannot load template")
-	ErrExecTemplate = errors.New("cannot execute template")
-	ErrUploadFailed = errors.New("upload failed")
+	ErrLoadObj                = errors.New("cannot load object")
+	ErrLoadTemplate           = errors.New("cannot load template")
+	ErrExecTemplate           = errors.New("cannot execute template")
+	ErrInvalidSchedulerConfig = errors.New("invalid kube-scheduler configuration")
+	ErrGenSchedulerConfig     = errors.New("cannot generate kube-scheduler configuration")
+	ErrGenKWOKConfig          = errors.New("cannot generate KWOK configuration")
+	ErrUploadFailed           = errors.New("upload failed")
+	ErrWaitScheduled          = errors.New("cannot wait for pods to be scheduled")
+	ErrSimulateLifecycle      = errors.New("cannot simulate node and pod lifecycle")
 
+	ErrSecretData     = errors.New("cannot process secret data")
 	ErrSaveObj        = errors.New("cannot save object")
 	ErrDownloadFailed = errors.New("download failed")
+	ErrWatchFailed    = errors.New("watch failed")
+	ErrReplayFailed   = errors.New("replay failed")
+	ErrCopyFailed     = errors.New("copy failed")
+	ErrUnsafeCopy     = errors.New("unsafe copy")
+	ErrDiffFailed     = errors.New("diff failed")
+	ErrInspectFailed  = errors.New("inspect failed")
+	ErrReportFailed   = errors.New("report failed")
+	ErrSimulateFailed = errors.New("simulation failed")
+	ErrValidateFailed = errors.New("validation failed")
 )
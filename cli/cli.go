@@ -7,23 +7,115 @@ import (
 	flag "github.com/spf13/pflag"
 	"k8s.io/client-go/tools/clientcmd"
 	"os"
+	"path/filepath"
+	"time"
 )
 
+// DefaultWaitScheduledTimeout is the timeout of upload --wait-scheduled when given without value.
+const DefaultWaitScheduledTimeout = 10 * time.Minute
+
+// DefaultKWOKConfigFilename is the file name of the KWOK configuration written by upload --kwok within the obj dir
+// when --kwok-config is not given.
+const DefaultKWOKConfigFilename = "kwok-config.yaml"
+
+// DefaultUploadReportFilename is the file name of the upload report written by upload within the obj dir when
+// --upload-report is not given.
+const DefaultUploadReportFilename = "upload-report.json"
+
+// NoUploadReport is the value of --upload-report that disables writing the upload report.
+const NoUploadReport = "none"
+
 type MainOpts struct {
 	api.CopierConfig
 	ObjDir                  string
 	KubeSchedulerConfigPath string
+	SchedulerConfig         api.SchedulerConfigOpts
+	// SchedulerName is the scheduler profile used by simulate for all pods instead of their scheduler name.
+	SchedulerName string
+	// KWOKConfigPath is the path where upload writes the KWOK configuration if KWOK is set.
+	KWOKConfigPath string
+	// GenSchedulerConfig indicates whether upload should also write the kube-scheduler config for the target.
+	GenSchedulerConfig bool
+	// SchedulerPluginWeights are the unparsed score plugin weights of SchedulerConfig.
+	SchedulerPluginWeights []string
+	// OnExists and OnForbidden are the unparsed policies of api.CopierConfig.UploadPolicy.
+	OnExists    []string
+	OnForbidden []string
+
+	Replay api.ReplayOpts
+
+	OutputFormat api.OutputFormat
+	// IgnoreFields are the field paths ignored when diffing objects.
+	IgnoreFields []string
+	// TopN is the number of top entries reported by inspect.
+	TopN int
+
+	// KubeConfigExpiration is the validity duration of generated shoot kubeconfigs.
+	KubeConfigExpiration time.Duration
+	// AdminKubeConfig indicates whether an admin kubeconfig should also be generated.
+	AdminKubeConfig bool
+	// GenDir is the directory where generated kubeconfigs are written.
+	GenDir string
+
+	// WaitScheduled is the timeout for waiting till all uploaded pods are bound or unschedulable. Zero disables waiting.
+	WaitScheduled time.Duration
+	// SimulateLifecycle indicates whether upload should keep nodes Ready and mark bound pods Running until interrupted.
+	SimulateLifecycle bool
+	// LifecycleInterval is the refresh interval of SimulateLifecycle.
+	LifecycleInterval time.Duration
+
+	// Closure indicates whether download should fetch only the objects matching Selector and their dependencies.
+	Closure  bool
+	Selector api.ObjSelector
+}
+
+func setupRetryFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
+	flagSet.IntVar(&mainOpts.Retry.MaxAttempts, "max-attempts", 5, "maximum number of attempts of list and get requests failing with transient errors like timeouts, 429, 5xx and connection resets and of create requests failing with 429 or refused connections - 1 disables retries")
+	flagSet.DurationVar(&mainOpts.Retry.InitialBackoff, "retry-initial-backoff", 500*time.Millisecond, "backoff before the first retry, doubled with jitter for every further retry unless the server suggests a longer Retry-After")
+	flagSet.DurationVar(&mainOpts.Retry.MaxBackoff, "retry-max-backoff", 30*time.Second, "maximum backoff between retries")
+}
+
+func setupRateLimitFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
+	flagSet.Float32Var(&mainOpts.QPS, "qps", 0, "client-side QPS limit of kube clients - defaults to 1.5 x pool-size")
+	flagSet.IntVar(&mainOpts.Burst, "burst", 0, "client-side burst limit of kube clients - defaults to pool-size")
+	flagSet.BoolVar(&mainOpts.AdaptiveConcurrency, "adaptive-concurrency", false, "whether to start with a quarter of pool-size concurrent requests, halve them on throttling and timeouts and increase them up to pool-size while latency is healthy")
+}
+
+// validateClientOpts validates the retry and rate limit flags.
+func validateClientOpts(mo *MainOpts) (exitCode int, err error) {
+	switch {
+	case mo.QPS < 0 || mo.Burst < 0:
+		err = fmt.Errorf("%w: qps and burst must not be negative", api.ErrInvalidOpt)
+	case mo.Retry.MaxAttempts < 1:
+		err = fmt.Errorf("%w: max attempts must be positive: %d", api.ErrInvalidOpt, mo.Retry.MaxAttempts)
+	case mo.Retry.InitialBackoff < 0 || mo.Retry.MaxBackoff < 0:
+		err = fmt.Errorf("%w: retry backoffs must not be negative", api.ErrInvalidOpt)
+	}
+	if err != nil {
+		exitCode = ExitMandatoryOpt
+	}
+	return
 }
 
 func setupCommonFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
 	flagSet.StringVarP(&mainOpts.KubeConfigPath, clientcmd.RecommendedConfigPathFlag, "k", os.Getenv(clientcmd.RecommendedConfigPathEnvVar), "kubeconfig path of shoot data plane cluster - defaults to KUBECONFIG env-var")
-	//downloadFlags.StringVarP(&mainOpts.ControlKubeConfigPath, "kubeconfig-control", "c", os.Getenv("CONTROL_KUBECONFIG"), "kubeconfig path of shoot control plane (seed kubeconfig) - defaults to CONTROL_KUBECONFIG env-var")
 	flagSet.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "Base directory where object YAML's of cluster were downloaded using 'download' sub-command")
-	flagSet.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size") //TODO: solve the connection reset by peer issue when pool size increases
+	flagSet.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size - see --qps, --burst and --adaptive-concurrency to limit requests to the API server")
+	setupRetryFlagsToOpts(flagSet, mainOpts)
+	setupRateLimitFlagsToOpts(flagSet, mainOpts)
 }
 func SetupDownloadFlagsToOpts(downloadFlags *flag.FlagSet, mainOpts *MainOpts) {
 	setupCommonFlagsToOpts(downloadFlags, mainOpts)
-	//downloadFlags.StringVarP(&mainOpts.ControlKubeConfigPath, "kubeconfig-control", "c", os.Getenv("CONTROL_KUBECONFIG"), "kubeconfig path of shoot control plane (seed kubeconfig) - defaults to CONTROL_KUBECONFIG env-var")
+	setupShootFlagsToOpts(downloadFlags, mainOpts)
+	downloadFlags.StringVarP(&mainOpts.ControlKubeConfigPath, "kubeconfig-control", "c", os.Getenv("CONTROL_KUBECONFIG"), "kubeconfig path of shoot control plane (seed kubeconfig) - defaults to CONTROL_KUBECONFIG env-var")
+	downloadFlags.BoolVar(&mainOpts.NodeTemplates, "node-templates", false, "whether to also generate synthetic template nodes for each worker pool and zone of the shoot into the node-templates/ subtree of the obj dir")
+	downloadFlags.StringVar(&mainOpts.ControlNamespace, "control-namespace", "", "control-plane namespace of the shoot in the seed - defaults to shoot--<project>--<shoot>")
+	downloadFlags.BoolVar(&mainOpts.IncludeSecretData, "include-secret-data", false, "whether to download secret values as-is instead of redacting them")
+	downloadFlags.BoolVar(&mainOpts.Incremental, "incremental", false, "whether to only rewrite objects whose resourceVersion changed since the previous download into the obj dir, delete objects that no longer exist and append a changelog")
+	downloadFlags.BoolVar(&mainOpts.Closure, "closure", false, "whether to download only objects of the given GVRs (default: pods) matching the selector and all objects needed to schedule them")
+	downloadFlags.StringVarP(&mainOpts.Selector.LabelSelector, "selector", "l", "", "label selector for the root objects of the closure download")
+	downloadFlags.StringVar(&mainOpts.Selector.FieldSelector, "field-selector", "", "field selector for the root objects of the closure download")
+	downloadFlags.StringVarP(&mainOpts.Selector.Namespace, "namespace", "n", "", "namespace of the root objects of the closure download - defaults to all namespaces")
 	standardUsage := downloadFlags.PrintDefaults
 	downloadFlags.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s download <flags> <GVRs>\n", api.ProgramName)
@@ -35,13 +127,33 @@ func SetupDownloadFlagsToOpts(downloadFlags *flag.FlagSet, mainOpts *MainOpts) {
 		_, _ = fmt.Fprintln(os.Stderr)
 		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
 		_, _ = fmt.Fprintf(os.Stderr, "%s download -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir  pods nodes scheduling.k8s.io/v1/priorityclasses\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s download -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir  secrets pods  # secret values are redacted unless --include-secret-data\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s download -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --closure -n default --field-selector status.phase=Pending -l app=nginx\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s download -g /tmp/garden-kubeconfig.yaml --landscape live --project dev --shoot aw -d /tmp/myobjdir\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "  Download by shoot coordinates using a short-lived viewer kubeconfig generated via the garden cluster. Ignored if -k is given explicitly.")
+		_, _ = fmt.Fprintf(os.Stderr, "%s download -k /tmp/mykubeconfig.yaml -c /tmp/myseedkubeconfig.yaml --project dev --shoot aw -d /tmp/myobjdir\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "  Also download machine and cluster-autoscaler objects of the shoot control-plane from the seed into the control/ subtree of the obj dir.")
+		_, _ = fmt.Fprintf(os.Stderr, "%s download -g /tmp/garden-kubeconfig.yaml --landscape live --project dev --shoot aw -d /tmp/myobjdir --node-templates\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "  Also generate template nodes for the worker pools of the shoot into the node-templates/ subtree of the obj dir.")
 		_, _ = fmt.Fprintln(os.Stderr, "  Generate Viewer KubeConfigPath. See: https://github.com/gardener/gardener/blob/23bf7c2dd2e63b338accc68c5b53c1209e9df79a/docs/usage/shoot/shoot_access.md#shootsviewerkubeconfig-subresource")
 	}
 }
 func SetupUploadFlagsToOpts(uploadFlags *flag.FlagSet, mainOpts *MainOpts) {
 	setupCommonFlagsToOpts(uploadFlags, mainOpts)
 	uploadFlags.StringVarP(&mainOpts.KubeSchedulerConfigPath, "scheduler-config", "s", "/tmp/kube-scheduler-config.yaml", "kube-scheduler config path")
+	uploadFlags.BoolVar(&mainOpts.GenSchedulerConfig, "gen-scheduler-config", true, "whether to write the kube-scheduler config for the target after upload - see 'gen-scheduler-config' sub-command")
+	setupSchedulerConfigFlagsToOpts(uploadFlags, mainOpts)
+	setupUploadPolicyFlagsToOpts(uploadFlags, mainOpts)
 	uploadFlags.BoolVarP(&mainOpts.OrderKinds, "order-kinds", "o", true, "whether to order kinds by priority and wait while uploading")
+	uploadFlags.BoolVar(&mainOpts.DummySecretData, "dummy-secret-data", false, "whether to regenerate secret values with random dummy values of the same length")
+	uploadFlags.StringVar(&mainOpts.UploadReportPath, "upload-report", "", "path of the JSON report of the outcome of every uploaded object - defaults to <obj-dir>/"+DefaultUploadReportFilename+", '"+NoUploadReport+"' disables writing the report")
+	uploadFlags.IntVar(&mainOpts.MaxUploadFailures, "max-upload-failures", 0, "number of objects that may fail to upload before upload stops and exits non-zero")
+	uploadFlags.BoolVar(&mainOpts.KWOK, "kwok", false, "whether to annotate uploaded nodes with kwok.x-k8s.io/node=fake and write a KWOK configuration keeping them Ready and advancing their pods to Running")
+	uploadFlags.StringVar(&mainOpts.KWOKConfigPath, "kwok-config", "", "KWOK configuration path written if --kwok is given - defaults to <obj-dir>/"+DefaultKWOKConfigFilename)
+	uploadFlags.DurationVar(&mainOpts.WaitScheduled, "wait-scheduled", 0, "timeout for waiting after upload till every uploaded pod is bound or unschedulable and printing a scheduling summary - defaults to 10m if given without value")
+	uploadFlags.Lookup("wait-scheduled").NoOptDefVal = DefaultWaitScheduledTimeout.String()
+	uploadFlags.BoolVar(&mainOpts.SimulateLifecycle, "simulate-lifecycle", false, "whether to keep node Ready conditions and leases fresh and mark bound pods Running after upload until interrupted - an alternative to --kwok needing no extra process")
+	uploadFlags.DurationVar(&mainOpts.LifecycleInterval, "lifecycle-interval", 10*time.Second, "refresh interval of --simulate-lifecycle")
 	standardUsage := uploadFlags.PrintDefaults
 	uploadFlags.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s upload <flags>\n", api.ProgramName)
@@ -51,7 +163,480 @@ func SetupUploadFlagsToOpts(uploadFlags *flag.FlagSet, mainOpts *MainOpts) {
 		_, _ = fmt.Fprintln(os.Stderr)
 		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
 		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir")
+		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --wait-scheduled=5m")
+		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --on-exists=update --on-exists=Pod=replace --on-forbidden=fail")
+		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --simulate-lifecycle --wait-scheduled")
+		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --kwok && kwok --kubeconfig /tmp/mykubeconfig.yaml --config /tmp/myobjdir/kwok-config.yaml")
+		_, _ = fmt.Fprintln(os.Stderr, "kcpcl upload -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir --scheduler-profile /tmp/myprofile.yaml --scheduler-plugin-weight bin-packing-scheduler:NodeResourcesFit=5")
+	}
+}
+
+func SetupGenSchedulerConfigFlagsToOpts(genFlags *flag.FlagSet, mainOpts *MainOpts) {
+	genFlags.StringVarP(&mainOpts.KubeConfigPath, clientcmd.RecommendedConfigPathFlag, "k", os.Getenv(clientcmd.RecommendedConfigPathEnvVar), "kubeconfig path of target cluster referenced by the kube-scheduler config - defaults to KUBECONFIG env-var")
+	genFlags.StringVarP(&mainOpts.KubeSchedulerConfigPath, "scheduler-config", "s", "", "kube-scheduler config path - defaults to stdout")
+	genFlags.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "pool size of the upload to the target cluster that --qps and --burst default to")
+	genFlags.Float32Var(&mainOpts.QPS, "qps", 0, "client-side QPS limit of kube-scheduler - defaults to 1.5 x pool-size")
+	genFlags.IntVar(&mainOpts.Burst, "burst", 0, "client-side burst limit of kube-scheduler - defaults to pool-size")
+	setupSchedulerConfigFlagsToOpts(genFlags, mainOpts)
+	standardUsage := genFlags.PrintDefaults
+	genFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s gen-scheduler-config <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s gen-scheduler-config -k /tmp/kvcl.yaml\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s gen-scheduler-config -k /tmp/kvcl.yaml -s /tmp/kube-scheduler-config.yaml --scheduler-profile /tmp/myprofile.yaml\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForGenSchedulerConfig(mo *MainOpts) (exitCode int, err error) {
+	if mo.KubeConfigPath == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingKubeConfig
+		return
+	}
+	if mo.QPS < 0 || mo.Burst < 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: qps and burst must not be negative", api.ErrInvalidOpt)
+		return
+	}
+	return validateSchedulerConfigOpts(mo)
+}
+
+func setupUploadPolicyFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
+	flagSet.StringArrayVar(&mainOpts.OnExists, "on-exists", nil, "policy for objects that already exist: skip|fail|update|replace - give <kind>=<policy> to override it per kind - can be repeated - defaults to skip")
+	flagSet.StringArrayVar(&mainOpts.OnForbidden, "on-forbidden", nil, "policy for objects whose creation, update or replace is forbidden: skip|fail - give <kind>=<policy> to override it per kind - can be repeated - defaults to skip")
+}
+
+// validateUploadPolicyOpts parses the upload policy flags.
+func validateUploadPolicyOpts(mo *MainOpts) (exitCode int, err error) {
+	mo.UploadPolicy, err = api.ParseUploadPolicy(mo.OnExists, mo.OnForbidden)
+	if err != nil {
+		exitCode = ExitMandatoryOpt
+	}
+	return
+}
+
+func setupSchedulerConfigFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
+	flagSet.StringVar(&mainOpts.SchedulerConfig.TemplatePath, "scheduler-config-template", "", "path of kube-scheduler config template used instead of the embedded template")
+	flagSet.StringArrayVar(&mainOpts.SchedulerConfig.ProfilePaths, "scheduler-profile", nil, "path of KubeSchedulerProfile YAML fragment replacing the profile of the same scheduler name or added as new profile - can be repeated")
+	flagSet.StringArrayVar(&mainOpts.SchedulerConfig.ExtenderPaths, "scheduler-extender", nil, "path of scheduler Extender YAML fragment added to the extenders - can be repeated")
+	flagSet.Int32Var(&mainOpts.SchedulerConfig.PercentageOfNodesToScore, "percentage-of-nodes-to-score", 0, "percentage of feasible nodes scored per pod - defaults to kube-scheduler default")
+	flagSet.Int32Var(&mainOpts.SchedulerConfig.Parallelism, "scheduler-parallelism", 0, "number of parallel scheduling workers - defaults to kube-scheduler default")
+	flagSet.StringArrayVar(&mainOpts.SchedulerPluginWeights, "scheduler-plugin-weight", nil, "score plugin weight in format <profile>:<plugin>=<weight> - can be repeated")
+}
+
+// validateSchedulerConfigOpts validates the kube-scheduler config flags and parses the plugin weights.
+func validateSchedulerConfigOpts(mo *MainOpts) (exitCode int, err error) {
+	if mo.SchedulerConfig.PercentageOfNodesToScore < 0 || mo.SchedulerConfig.PercentageOfNodesToScore > 100 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: percentage-of-nodes-to-score must be in [0, 100]", api.ErrInvalidOpt)
+		return
+	}
+	if mo.SchedulerConfig.Parallelism < 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: scheduler-parallelism must not be negative", api.ErrInvalidOpt)
+		return
+	}
+	mo.SchedulerConfig.PluginWeights, err = api.ParsePluginWeights(mo.SchedulerPluginWeights)
+	if err != nil {
+		exitCode = ExitMandatoryOpt
+	}
+	return
+}
+
+func SetupWatchFlagsToOpts(watchFlags *flag.FlagSet, mainOpts *MainOpts) {
+	setupCommonFlagsToOpts(watchFlags, mainOpts)
+	watchFlags.BoolVar(&mainOpts.IncludeSecretData, "include-secret-data", false, "whether to download secret values as-is instead of redacting them")
+	watchFlags.BoolVarP(&mainOpts.RecordEvents, "record-events", "r", false, "whether to append observed object events with timestamps to the event log in the obj dir")
+	standardUsage := watchFlags.PrintDefaults
+	watchFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s watch <flags> <GVRs>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<GVRs>: GVRs in format [group/][version/]resource where group and version can be omitted for defaults")
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s watch -k /tmp/mykubeconfig.yaml -d /tmp/myobjdir -r pods nodes\n", api.ProgramName)
+	}
+}
+
+func SetupReplayFlagsToOpts(replayFlags *flag.FlagSet, mainOpts *MainOpts) {
+	setupCommonFlagsToOpts(replayFlags, mainOpts)
+	replayFlags.Float64Var(&mainOpts.Replay.Speed, "speed", 1, "factor by which replay is faster than the original timing")
+	replayFlags.DurationVar(&mainOpts.Replay.Start, "start", 0, "offset from the first event before which events are skipped")
+	replayFlags.DurationVar(&mainOpts.Replay.Stop, "stop", 0, "offset from the first event after which replay stops - defaults to end of event log")
+	replayFlags.BoolVar(&mainOpts.DummySecretData, "dummy-secret-data", false, "whether to regenerate secret values with random dummy values of the same length")
+	setupUploadPolicyFlagsToOpts(replayFlags, mainOpts)
+	standardUsage := replayFlags.PrintDefaults
+	replayFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s replay <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s replay -k /tmp/kvcl.yaml -d /tmp/myobjdir --speed 10 --start 5m --stop 1h\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "  Replays the event log recorded by '%s watch -r' into the target. Upload the obj dir first to establish the base state.\n", api.ProgramName)
+	}
+}
+
+func SetupCopyFlagsToOpts(copyFlags *flag.FlagSet, mainOpts *MainOpts) {
+	copyFlags.StringVarP(&mainOpts.KubeConfigPath, "source-kubeconfig", "k", os.Getenv(clientcmd.RecommendedConfigPathEnvVar), "kubeconfig path of source cluster - defaults to KUBECONFIG env-var")
+	copyFlags.StringVarP(&mainOpts.TargetKubeConfigPath, "target-kubeconfig", "t", "", "kubeconfig path of target cluster")
+	copyFlags.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "optional directory where copied source objects are also saved in the 'download' layout")
+	copyFlags.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size - see --qps, --burst and --adaptive-concurrency to limit requests to the API server")
+	copyFlags.BoolVar(&mainOpts.IncludeSecretData, "include-secret-data", false, "whether to copy secret values as-is instead of redacting them")
+	copyFlags.BoolVar(&mainOpts.DummySecretData, "dummy-secret-data", false, "whether to regenerate secret values with random dummy values of the same length")
+	setupUploadPolicyFlagsToOpts(copyFlags, mainOpts)
+	setupRetryFlagsToOpts(copyFlags, mainOpts)
+	setupRateLimitFlagsToOpts(copyFlags, mainOpts)
+	standardUsage := copyFlags.PrintDefaults
+	copyFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s copy <flags> <GVRs>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<GVRs>: GVRs in format [group/][version/]resource where group and version can be omitted for defaults")
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s copy -k gen/mycluster.yaml -t /tmp/kvcl.yaml\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s copy -k gen/mycluster.yaml -t /tmp/kvcl.yaml -d /tmp/myobjdir pods nodes\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForCopy(mo *MainOpts) (exitCode int, err error) {
+	if mo.KubeConfigPath == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingKubeConfig
+		return
+	}
+	if mo.TargetKubeConfigPath == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingTargetKubeConfig
+		return
+	}
+	exitCode, err = validateClientOpts(mo)
+	if err != nil {
+		return
 	}
+	return validateUploadPolicyOpts(mo)
+}
+
+func SetupCopyShootFlagsToOpts(copyFlags *flag.FlagSet, mainOpts *MainOpts) {
+	setupShootFlagsToOpts(copyFlags, mainOpts)
+	copyFlags.StringVar(&mainOpts.TargetShoot.Project, "target-project", "", "gardener project of the target shoot - defaults to the project of the source shoot")
+	copyFlags.StringVar(&mainOpts.TargetShoot.Name, "target-shoot", "", "gardener shoot name of the target shoot")
+	copyFlags.BoolVar(&mainOpts.DryRun, "dry-run", false, "whether to only preview the objects that would be copied")
+	copyFlags.BoolVar(&mainOpts.ForceKubeSystem, "force-kube-system", false, "whether to also copy objects in the kube-system namespace")
+	copyFlags.BoolVar(&mainOpts.DummySecretData, "dummy-secret-data", false, "whether to regenerate secret values with random dummy values of the same length")
+	copyFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputText), "output format of the copy report: text|json|yaml")
+	copyFlags.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size - see --qps, --burst and --adaptive-concurrency to limit requests to the API server")
+	setupRetryFlagsToOpts(copyFlags, mainOpts)
+	setupRateLimitFlagsToOpts(copyFlags, mainOpts)
+	standardUsage := copyFlags.PrintDefaults
+	copyFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s copyshoot <flags> <GVRs>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<GVRs>: mandatory allowlist of GVRs to copy in format [group/][version/]resource where group and version can be omitted for defaults")
+		_, _ = fmt.Fprintln(os.Stderr, "Nodes, objects bound to nodes and, unless --force-kube-system, objects in kube-system are never copied. Existing objects in the target are left untouched.")
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s copyshoot -g /tmp/garden-kubeconfig.yaml --landscape live --project dev --shoot aw --target-shoot aw2 --dry-run namespaces configmaps apps/v1/deployments\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForCopyShoot(mo *MainOpts, args []string) (exitCode int, err error) {
+	exitCode, err = validateShootOpts(mo)
+	if err != nil {
+		return
+	}
+	exitCode, err = validateClientOpts(mo)
+	if err != nil {
+		return
+	}
+	if mo.TargetShoot.Project == "" {
+		mo.TargetShoot.Project = mo.Shoot.Project
+	}
+	mo.TargetShoot.Landscape = mo.Shoot.Landscape
+	switch mo.OutputFormat {
+	case api.OutputText, api.OutputJSON, api.OutputYAML:
+	default:
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	exitCode = ExitMandatoryOpt
+	switch {
+	case mo.TargetShoot.Name == "":
+		err = fmt.Errorf("%w: missing --target-shoot", api.ErrMissingShoot)
+	case mo.TargetShoot == mo.Shoot:
+		err = fmt.Errorf("%w: source and target shoot %s are the same", api.ErrUnsafeCopy, mo.Shoot)
+	case len(args) == 0:
+		exitCode = ExitMissingArgs
+		err = fmt.Errorf("%w: an allowlist of GVRs to copy is mandatory", api.ErrMissingGVRs)
+	default:
+		exitCode = ExitSuccess
+	}
+	return
+}
+
+func SetupDiffFlagsToOpts(diffFlags *flag.FlagSet, mainOpts *MainOpts, defaultIgnoreFields []string) {
+	diffFlags.StringVarP(&mainOpts.KubeConfigPath, clientcmd.RecommendedConfigPathFlag, "k", "", "kubeconfig path of cluster to diff the obj dir against instead of a second obj dir")
+	diffFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputText), "output format: text|json")
+	diffFlags.StringSliceVar(&mainOpts.IgnoreFields, "ignore-fields", defaultIgnoreFields, "field paths ignored when diffing where '*' matches any map key or list index")
+	diffFlags.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size")
+	standardUsage := diffFlags.PrintDefaults
+	diffFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s diff <flags> <dirA> [dirB]\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s diff /tmp/aw-yesterday /tmp/aw-today\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s diff -k /tmp/kvcl.yaml -o json /tmp/aw\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForDiff(mo *MainOpts, args []string) (exitCode int, err error) {
+	if mo.OutputFormat != api.OutputText && mo.OutputFormat != api.OutputJSON {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	numDirs := 2
+	if mo.KubeConfigPath != "" {
+		numDirs = 1
+	}
+	if len(args) != numDirs {
+		exitCode = ExitMissingArgs
+		err = fmt.Errorf("%w: expected %d obj dir(s), got %d", api.ErrMissingObjDir, numDirs, len(args))
+		return
+	}
+	var osFS = afero.NewOsFs()
+	for _, dir := range args {
+		ok, _ := afero.DirExists(osFS, dir)
+		if !ok {
+			exitCode = ExitObjDir
+			err = fmt.Errorf("%w: %q", api.ErrObjDirNotExist, dir)
+			return
+		}
+	}
+	return
+}
+
+func SetupReportPlacementFlagsToOpts(reportFlags *flag.FlagSet, mainOpts *MainOpts) {
+	reportFlags.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "Base directory where object YAML's of cluster were downloaded using 'download' sub-command")
+	reportFlags.StringVarP(&mainOpts.KubeConfigPath, clientcmd.RecommendedConfigPathFlag, "k", os.Getenv(clientcmd.RecommendedConfigPathEnvVar), "kubeconfig path of target cluster where the obj dir was uploaded - defaults to KUBECONFIG env-var")
+	reportFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputTable), "output format: table|json|yaml")
+	reportFlags.IntVarP(&mainOpts.PoolSize, "pool-size", "p", 160, "go-routine pool size")
+	standardUsage := reportFlags.PrintDefaults
+	reportFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s report placement <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s report placement -d /tmp/aw -k /tmp/kvcl.yaml\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s report placement -d /tmp/aw -k /tmp/kvcl.yaml -o json\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForReportPlacement(mo *MainOpts) (exitCode int, err error) {
+	switch mo.OutputFormat {
+	case api.OutputTable, api.OutputJSON, api.OutputYAML:
+	default:
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	if mo.KubeConfigPath == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingKubeConfig
+		return
+	}
+	return validateObjDirExists(mo.ObjDir)
+}
+
+func SetupSimulateFlagsToOpts(simulateFlags *flag.FlagSet, mainOpts *MainOpts) {
+	simulateFlags.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "Base directory where object YAML's of cluster were downloaded using 'download' sub-command")
+	simulateFlags.StringVarP(&mainOpts.KubeSchedulerConfigPath, "scheduler-config", "s", "", "kube-scheduler config path - defaults to the config generated by 'gen-scheduler-config'")
+	simulateFlags.StringVar(&mainOpts.SchedulerName, "scheduler-name", "", "scheduler profile used for all pods instead of their spec.schedulerName")
+	simulateFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputTable), "output format: table|json|yaml")
+	standardUsage := simulateFlags.PrintDefaults
+	simulateFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s simulate <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s simulate -d /tmp/aw --scheduler-name bin-packing-scheduler\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s simulate -d /tmp/aw -s /tmp/kube-scheduler-config.yaml -o json\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "  Runs kube-scheduler in-process with all in-tree plugins of the profiles. Extenders are not called.")
+	}
+}
+
+func ValidateMainOptsForSimulate(mo *MainOpts) (exitCode int, err error) {
+	switch mo.OutputFormat {
+	case api.OutputTable, api.OutputJSON, api.OutputYAML:
+	default:
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	return validateObjDirExists(mo.ObjDir)
+}
+
+func SetupInspectFlagsToOpts(inspectFlags *flag.FlagSet, mainOpts *MainOpts) {
+	inspectFlags.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "Base directory where object YAML's of cluster were downloaded using 'download' sub-command")
+	inspectFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputTable), "output format: table|json|yaml")
+	inspectFlags.IntVar(&mainOpts.TopN, "top", 10, "number of namespaces with most pods to report")
+	standardUsage := inspectFlags.PrintDefaults
+	inspectFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s inspect <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s inspect -d /tmp/myobjdir\n", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s inspect -d /tmp/myobjdir -o json --top 5\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForInspect(mo *MainOpts) (exitCode int, err error) {
+	switch mo.OutputFormat {
+	case api.OutputTable, api.OutputJSON, api.OutputYAML:
+	default:
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	if mo.TopN < 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: top must not be negative: %d", api.ErrInvalidOpt, mo.TopN)
+		return
+	}
+	return validateObjDirExists(mo.ObjDir)
+}
+
+// validateObjDirExists validates that the given obj dir is specified and exists.
+func validateObjDirExists(objDir string) (exitCode int, err error) {
+	if objDir == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingObjDir
+		return
+	}
+	var osFS = afero.NewOsFs()
+	ok, err := afero.DirExists(osFS, objDir)
+	if err != nil {
+		exitCode = ExitObjDir
+		err = fmt.Errorf("%w: %w", api.ErrCantReadObjDir, err)
+		return
+	}
+	if !ok {
+		exitCode = ExitObjDir
+		err = fmt.Errorf("%w: %q", api.ErrObjDirNotExist, objDir)
+		return
+	}
+	return
+}
+
+func SetupValidateFlagsToOpts(validateFlags *flag.FlagSet, mainOpts *MainOpts) {
+	validateFlags.StringVarP(&mainOpts.ObjDir, "obj-dir", "d", "", "Base directory where object YAML's of cluster were downloaded using 'download' sub-command")
+	validateFlags.StringVarP((*string)(&mainOpts.OutputFormat), "output", "o", string(api.OutputText), "output format: text|json")
+	standardUsage := validateFlags.PrintDefaults
+	validateFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s validate <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s validate -d /tmp/myobjdir\n", api.ProgramName)
+	}
+}
+
+func ValidateMainOptsForValidate(mo *MainOpts) (exitCode int, err error) {
+	if mo.OutputFormat != api.OutputText && mo.OutputFormat != api.OutputJSON {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, mo.OutputFormat)
+		return
+	}
+	return validateObjDirExists(mo.ObjDir)
+}
+
+func setupShootFlagsToOpts(flagSet *flag.FlagSet, mainOpts *MainOpts) {
+	flagSet.StringVarP(&mainOpts.GardenKubeConfigPath, "garden-kubeconfig", "g", os.Getenv("GARDEN_KUBECONFIG"), "kubeconfig path of gardener garden cluster - defaults to GARDEN_KUBECONFIG env-var")
+	flagSet.StringVar(&mainOpts.Shoot.Landscape, "landscape", os.Getenv("LANDSCAPE"), "gardener landscape - defaults to LANDSCAPE env-var")
+	flagSet.StringVar(&mainOpts.Shoot.Project, "project", os.Getenv("PROJECT"), "gardener project - defaults to PROJECT env-var")
+	flagSet.StringVar(&mainOpts.Shoot.Name, "shoot", os.Getenv("SHOOT"), "gardener shoot name - defaults to SHOOT env-var")
+}
+
+func SetupGenKubeConfigFlagsToOpts(genFlags *flag.FlagSet, mainOpts *MainOpts) {
+	setupShootFlagsToOpts(genFlags, mainOpts)
+	genFlags.DurationVarP(&mainOpts.KubeConfigExpiration, "expiration", "e", 24*time.Hour, "validity duration of the generated kubeconfig")
+	genFlags.BoolVar(&mainOpts.AdminKubeConfig, "admin", false, "whether to also generate an admin kubeconfig")
+	genFlags.StringVar(&mainOpts.GenDir, "gen-dir", "gen", "directory where generated kubeconfigs are written as <context>.yaml")
+	standardUsage := genFlags.PrintDefaults
+	genFlags.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s genkubeconfig <flags>\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "<flags>")
+		standardUsage()
+		_, _ = fmt.Fprintln(os.Stderr)
+		_, _ = fmt.Fprintln(os.Stderr, "Examples:")
+		_, _ = fmt.Fprintf(os.Stderr, "%s genkubeconfig -g /tmp/garden-kubeconfig.yaml --project dev --shoot aw\n", api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "  Generate Viewer KubeConfigPath. See: https://github.com/gardener/gardener/blob/23bf7c2dd2e63b338accc68c5b53c1209e9df79a/docs/usage/shoot/shoot_access.md#shootsviewerkubeconfig-subresource")
+	}
+}
+
+// validateShootOpts validates the options needed to access a shoot by its gardener coordinates.
+func validateShootOpts(mo *MainOpts) (exitCode int, err error) {
+	exitCode = ExitMandatoryOpt
+	switch {
+	case mo.GardenKubeConfigPath == "" && mo.Shoot.Name == "":
+		err = api.ErrMissingShootKubeConfig
+	case mo.GardenKubeConfigPath == "":
+		err = api.ErrMissingKubeConfig
+	case mo.Shoot.Landscape == "":
+		err = api.ErrMissingLandscape
+	case mo.Shoot.Project == "":
+		err = api.ErrMissingProject
+	case mo.Shoot.Name == "":
+		err = api.ErrMissingShoot
+	default:
+		exitCode = ExitSuccess
+	}
+	return
+}
+
+func ValidateMainOptsForGenKubeConfig(mo *MainOpts) (exitCode int, err error) {
+	exitCode = ExitMandatoryOpt
+	switch {
+	case mo.GardenKubeConfigPath == "":
+		err = api.ErrMissingKubeConfig
+	case mo.Shoot.Project == "":
+		err = api.ErrMissingProject
+	case mo.Shoot.Name == "":
+		err = api.ErrMissingShoot
+	case mo.KubeConfigExpiration <= 0:
+		err = fmt.Errorf("%w: expiration must be positive: %s", api.ErrInvalidOpt, mo.KubeConfigExpiration)
+	default:
+		exitCode = ExitSuccess
+	}
+	return
 }
 
 func ValidateMainOptsCommon(mo *MainOpts) (exitCode int, err error) {
@@ -63,14 +648,49 @@ func ValidateMainOptsCommon(mo *MainOpts) (exitCode int, err error) {
 		exitCode = ExitMandatoryOpt
 		err = api.ErrObjDirNotExist
 	}
-	return
+	if err != nil {
+		return
+	}
+	return validateClientOpts(mo)
 }
 
 func ValidateMainOptsForDownload(mo *MainOpts, args []string) (exitCode int, err error) {
-	exitCode, err = ValidateMainOptsCommon(mo)
+	exitCode, err = validateClientOpts(mo)
 	if err != nil {
 		return
 	}
+	if mo.KubeConfigPath == "" {
+		exitCode, err = validateShootOpts(mo)
+		if err != nil {
+			return
+		}
+	}
+	if mo.ObjDir == "" {
+		exitCode = ExitMandatoryOpt
+		err = api.ErrMissingObjDir
+		return
+	}
+	if !mo.Closure && mo.Selector != (api.ObjSelector{}) {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: selector flags require --closure", api.ErrInvalidOpt)
+		return
+	}
+	if mo.Closure && mo.Incremental {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: --incremental cannot be combined with --closure", api.ErrInvalidOpt)
+		return
+	}
+	if mo.ControlKubeConfigPath != "" && mo.ControlNamespace == "" && (mo.Shoot.Project == "" || mo.Shoot.Name == "") {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: --kubeconfig-control requires --control-namespace or --project and --shoot", api.ErrInvalidOpt)
+		return
+	}
+	if mo.NodeTemplates {
+		exitCode, err = validateShootOpts(mo)
+		if err != nil {
+			err = fmt.Errorf("%w: --node-templates requires the shoot coordinates: %w", api.ErrInvalidOpt, err)
+		}
+	}
 	return
 }
 func ValidateMainOptsForUpload(mo *MainOpts) (exitCode int, err error) {
@@ -99,5 +719,50 @@ func ValidateMainOptsForUpload(mo *MainOpts) (exitCode int, err error) {
 		err = fmt.Errorf("%w: %q", api.ErrObjDirNotExist, mo.ObjDir)
 		return
 	}
+	if mo.MaxUploadFailures < 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: max upload failures must not be negative: %d", api.ErrInvalidOpt, mo.MaxUploadFailures)
+		return
+	}
+	if mo.SimulateLifecycle && mo.KWOK {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: --simulate-lifecycle and --kwok are mutually exclusive", api.ErrInvalidOpt)
+		return
+	}
+	if mo.SimulateLifecycle && mo.LifecycleInterval <= 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: lifecycle interval must be positive: %v", api.ErrInvalidOpt, mo.LifecycleInterval)
+		return
+	}
+	if mo.KWOK && mo.KWOKConfigPath == "" {
+		mo.KWOKConfigPath = filepath.Join(mo.ObjDir, DefaultKWOKConfigFilename)
+	}
+	switch mo.UploadReportPath {
+	case "":
+		mo.UploadReportPath = filepath.Join(mo.ObjDir, DefaultUploadReportFilename)
+	case NoUploadReport:
+		mo.UploadReportPath = ""
+	}
+	exitCode, err = validateUploadPolicyOpts(mo)
+	if err != nil {
+		return
+	}
+	return validateSchedulerConfigOpts(mo)
+}
+
+func ValidateMainOptsForReplay(mo *MainOpts) (exitCode int, err error) {
+	exitCode, err = ValidateMainOptsForUpload(mo)
+	if err != nil {
+		return
+	}
+	if mo.Replay.Speed <= 0 {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: speed must be positive: %v", api.ErrInvalidOpt, mo.Replay.Speed)
+		return
+	}
+	if mo.Replay.Stop != 0 && mo.Replay.Stop < mo.Replay.Start {
+		exitCode = ExitMandatoryOpt
+		err = fmt.Errorf("%w: stop %s is before start %s", api.ErrInvalidOpt, mo.Replay.Stop, mo.Replay.Start)
+	}
 	return
 }
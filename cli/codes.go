@@ -15,6 +15,19 @@ const (
 	ExitObjDir
 	ExitDownloadFailed
 	ExitUploadFailed
+	ExitWatchFailed
+	ExitReplayFailed
+	ExitCopyFailed
+	ExitDiffFailed
+	ExitInspectFailed
+	ExitValidateFailed
+	ExitGenKubeConfigFailed
+	ExitWaitScheduledFailed
+	ExitReportFailed
+	ExitGenSchedulerConfigFailed
+	ExitSimulateFailed
+	ExitGenKWOKConfigFailed
+	ExitSimulateLifecycleFailed
 
 	ExitValidateGVR
 	ExitGeneral = 255
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/elankath/kcpcl/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// adaptiveTargetLatency is the request latency up to which the adaptiveLimiter considers the API server healthy.
+const adaptiveTargetLatency = time.Second
+
+// adaptiveLimiter limits the number of concurrent requests using additive increase and multiplicative decrease. The
+// limit is halved when a request is throttled or times out, at most once per adaptiveTargetLatency so that a burst
+// of failures of requests that were in flight together only counts once. It is increased by one after as many
+// consecutive requests as the current limit completed within adaptiveTargetLatency. A nil limiter does not limit.
+type adaptiveLimiter struct {
+	mu           sync.Mutex
+	cond         *sync.Cond
+	limit        int
+	maxLimit     int
+	inFlight     int
+	numHealthy   int
+	lastDecrease time.Time
+	stats        api.ConcurrencyStats
+}
+
+func newAdaptiveLimiter(maxLimit int) *adaptiveLimiter {
+	maxLimit = max(1, maxLimit)
+	limit := max(1, maxLimit/4)
+	l := &adaptiveLimiter{
+		limit:    limit,
+		maxLimit: maxLimit,
+		stats:    api.ConcurrencyStats{Min: limit, Max: limit, Final: limit},
+	}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks till fewer requests than the limit are in flight or the context is done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cond.Broadcast()
+	})
+	defer stop()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inFlight >= l.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.cond.Wait()
+	}
+	l.inFlight++
+	return nil
+}
+
+// release marks a request acquired before as completed with the given latency and error and adapts the limit.
+func (l *adaptiveLimiter) release(latency time.Duration, err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	now := time.Now()
+	switch {
+	case isThrottled(err):
+		l.numHealthy = 0
+		if now.Sub(l.lastDecrease) < adaptiveTargetLatency || l.limit == 1 {
+			break
+		}
+		l.lastDecrease = now
+		l.setLimit(l.limit / 2)
+		l.stats.NumDecreases++
+		slog.Warn("Reduced concurrency after throttling.", "limit", l.limit, "error", err)
+	case latency <= adaptiveTargetLatency:
+		l.numHealthy++
+		if l.numHealthy >= l.limit && l.limit < l.maxLimit {
+			l.numHealthy = 0
+			l.setLimit(l.limit + 1)
+			slog.Debug("Increased concurrency.", "limit", l.limit)
+		}
+	default:
+		l.numHealthy = 0
+	}
+	l.cond.Broadcast()
+}
+
+func (l *adaptiveLimiter) setLimit(limit int) {
+	l.limit = max(1, min(limit, l.maxLimit))
+	l.stats.Min = min(l.stats.Min, l.limit)
+	l.stats.Max = max(l.stats.Max, l.limit)
+	l.stats.Final = l.limit
+}
+
+// concurrencyStats returns the stats of the limiter or nil for a nil limiter.
+func (l *adaptiveLimiter) concurrencyStats() *api.ConcurrencyStats {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	return &stats
+}
+
+// isThrottled returns whether the given error indicates that the API server is overloaded.
+func isThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsTooManyRequests(err) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
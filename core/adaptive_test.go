@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	l := newAdaptiveLimiter(16)
+	if l.limit != 4 {
+		t.Fatalf("expected initial limit 4, got %d", l.limit)
+	}
+	ctx := context.Background()
+	for range 4 {
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(cancelCtx); err == nil {
+		t.Errorf("expected acquire beyond limit to block till context is done")
+	}
+
+	for range 4 {
+		l.release(time.Millisecond, nil)
+	}
+	if l.limit != 5 {
+		t.Errorf("expected limit to increase to 5 after healthy requests, got %d", l.limit)
+	}
+	for range 2 {
+		if err := l.acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.release(time.Millisecond, apierrors.NewTooManyRequests("slow down", 1))
+	l.release(time.Millisecond, apierrors.NewTooManyRequests("slow down", 1))
+	stats := l.concurrencyStats()
+	if l.limit != 2 || stats.NumDecreases != 1 || stats.Min != 2 || stats.Max != 5 || stats.Final != 2 {
+		t.Errorf("expected single decrease to 2 for throttled requests in flight together, got limit=%d stats=%+v", l.limit, stats)
+	}
+	var nilLimiter *adaptiveLimiter
+	if err := nilLimiter.acquire(ctx); err != nil || nilLimiter.concurrencyStats() != nil {
+		t.Errorf("expected nil limiter not to limit")
+	}
+}
@@ -4,17 +4,19 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-func CreateKubeClient(kubeConfigPath string, poolSize int) (*kubernetes.Clientset, error) {
+// CreateKubeClient creates a clientset for the cluster of the given kubeconfig path whose requests are rate limited to
+// the given QPS and burst.
+func CreateKubeClient(kubeConfigPath string, qps float32, burst int) (*kubernetes.Clientset, error) {
 	restCfg, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
 	if err != nil {
 		return nil, err
 	}
-	restCfg.QPS = float32(poolSize)
-	restCfg.QPS = float32(poolSize) * 1.5
-	restCfg.Burst = poolSize
+	restCfg.QPS = qps
+	restCfg.Burst = burst
 	clientSet, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
 		return nil, err
@@ -22,13 +24,29 @@ func CreateKubeClient(kubeConfigPath string, poolSize int) (*kubernetes.Clientse
 	return clientSet, nil
 }
 
-func CreateDynamicAndDiscoveryClients(kubeConfigPath string, poolSize int) (dynamic.Interface, *discovery.DiscoveryClient, error) {
+// CreateDynamicAndDiscoveryClients creates clients for the cluster of the given kubeconfig path whose requests are
+// rate limited to the given QPS and burst.
+func CreateDynamicAndDiscoveryClients(kubeConfigPath string, qps float32, burst int) (dynamic.Interface, *discovery.DiscoveryClient, error) {
 	restCfg, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
 	if err != nil {
 		return nil, nil, err
 	}
-	restCfg.QPS = float32(poolSize) * 1.5
-	restCfg.Burst = poolSize
+	return createDynamicAndDiscoveryClients(restCfg, qps, burst)
+}
+
+// CreateDynamicAndDiscoveryClientsFromKubeConfig is like CreateDynamicAndDiscoveryClients but takes the kubeconfig
+// content instead of its path.
+func CreateDynamicAndDiscoveryClientsFromKubeConfig(kubeConfig []byte, qps float32, burst int) (dynamic.Interface, *discovery.DiscoveryClient, error) {
+	restCfg, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return createDynamicAndDiscoveryClients(restCfg, qps, burst)
+}
+
+func createDynamicAndDiscoveryClients(restCfg *rest.Config, qps float32, burst int) (dynamic.Interface, *discovery.DiscoveryClient, error) {
+	restCfg.QPS = qps
+	restCfg.Burst = burst
 	dyn, err := dynamic.NewForConfig(restCfg)
 	if err != nil {
 		return nil, nil, err
@@ -39,3 +57,11 @@ func CreateDynamicAndDiscoveryClients(kubeConfigPath string, poolSize int) (dyna
 	}
 	return dyn, disc, nil
 }
+
+func CreateDynamicClient(kubeConfigPath string) (dynamic.Interface, error) {
+	restCfg, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(restCfg)
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// closureObj is an object fetched while computing the dependency closure along with its GVR.
+type closureObj struct {
+	GVR schema.GroupVersionResource
+	Obj *unstructured.Unstructured
+}
+
+func (g *GardenerShootCopier) DownloadClosure(ctx context.Context, baseObjDir string, rootGVRs []schema.GroupVersionResource, selector api.ObjSelector) error {
+	slog.Info("Downloading object closure", "rootGVRs", rootGVRs, "selector", selector)
+	apiGroupResources, err := restmapper.GetAPIGroupResources(g.discoveryClient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch API group resources: %w", api.ErrDiscovery, err)
+	}
+	err = ValidateGVRs(apiGroupResources, rootGVRs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(apiGroupResources)
+
+	var level []closureObj
+	var numRetries int
+	listOpts := metav1.ListOptions{LabelSelector: selector.LabelSelector, FieldSelector: selector.FieldSelector}
+	for _, gvr := range rootGVRs {
+		isNamespaced, err := isNamespacedResource(apiGroupResources, gvr)
+		if err != nil {
+			return fmt.Errorf("%w: %w", api.ErrDiscovery, err)
+		}
+		var ri dynamic.ResourceInterface = g.dynamicClient.Resource(gvr)
+		if isNamespaced && selector.Namespace != "" {
+			ri = g.dynamicClient.Resource(gvr).Namespace(selector.Namespace)
+		}
+		objList, retries, err := g.listWithRetry(ctx, ri, listOpts)
+		numRetries += retries
+		if err != nil {
+			return fmt.Errorf("%w: failed to list objects for gvr %q with selector %+v: %w", api.ErrDownloadFailed, gvr, selector, err)
+		}
+		slog.Info("Selected root objects.", "gvr", gvr, "numObjs", len(objList.Items))
+		for i := range objList.Items {
+			level = append(level, closureObj{GVR: gvr, Obj: &objList.Items[i]})
+		}
+	}
+
+	visited := make(map[string]struct{})
+	for _, co := range level {
+		visited[closureKey(co.Obj.GroupVersionKind(), co.Obj.GetNamespace(), co.Obj.GetName())] = struct{}{}
+	}
+	numObjs := 0
+	for depth := 0; len(level) > 0; depth++ {
+		var refs []objRef
+		for _, co := range level {
+			err = g.writeClosureObj(baseObjDir, co)
+			if err != nil {
+				return err
+			}
+			numObjs++
+			coRefs, err := objRefs(co.Obj)
+			if err != nil {
+				return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+			}
+			for _, r := range coRefs {
+				key := closureKey(r.GVK, r.Namespace, r.Name)
+				if _, ok := visited[key]; ok {
+					continue
+				}
+				visited[key] = struct{}{}
+				refs = append(refs, r)
+			}
+		}
+		slog.Info("Resolved closure level.", "depth", depth, "numObjs", len(level), "numRefs", len(refs))
+		var retries int
+		level, retries, err = g.fetchRefs(ctx, mapper, refs)
+		numRetries += retries
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{nodesGVR, csiNodesGVR} {
+		objList, retries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(gvr), metav1.ListOptions{})
+		numRetries += retries
+		if err != nil {
+			return fmt.Errorf("%w: failed to list objects for gvr %q: %w", api.ErrDownloadFailed, gvr, err)
+		}
+		for i := range objList.Items {
+			err = g.writeClosureObj(baseObjDir, closureObj{GVR: gvr, Obj: &objList.Items[i]})
+			if err != nil {
+				return err
+			}
+			numObjs++
+		}
+	}
+	slog.Info("Downloaded object closure", "numObjs", numObjs, "numRetries", numRetries, "baseObjDir", baseObjDir)
+	return nil
+}
+
+// fetchRefs concurrently fetches the objects referenced by refs retrying transient errors and returns them along with
+// the number of retries. References to objects that are not found or that cannot be read are skipped with a warning.
+func (g *GardenerShootCopier) fetchRefs(ctx context.Context, mapper meta.RESTMapper, refs []objRef) (fetched []closureObj, numRetries int, err error) {
+	var mu sync.Mutex
+	taskGroup := g.pool.NewGroupContext(ctx)
+	for _, r := range refs {
+		restMapping, err := mapper.RESTMapping(r.GVK.GroupKind(), r.GVK.Version)
+		if err != nil {
+			slog.Warn("Skipping reference with unknown REST mapping.", "ref", r, "error", err)
+			continue
+		}
+		gvr := restMapping.Resource
+		taskGroup.SubmitErr(func() error {
+			var obj *unstructured.Unstructured
+			retries, err := retry(ctx, g.cfg.Retry, "get", isTransient, func() (err error) {
+				obj, err = g.dynamicClient.Resource(gvr).Namespace(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
+				return
+			})
+			mu.Lock()
+			numRetries += retries
+			mu.Unlock()
+			if err != nil {
+				if errors.IsNotFound(err) || errors.IsForbidden(err) {
+					slog.Warn("Skipping reference that cannot be fetched.", "ref", r, "error", err)
+					return nil
+				}
+				return fmt.Errorf("%w: failed to get %s: %w", api.ErrDownloadFailed, r, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			fetched = append(fetched, closureObj{GVR: gvr, Obj: obj})
+			return nil
+		})
+	}
+	err = taskGroup.Wait()
+	return
+}
+
+func (g *GardenerShootCopier) writeClosureObj(baseObjDir string, co closureObj) error {
+	if !g.cfg.IncludeSecretData {
+		err := RedactSecretData(co.Obj)
+		if err != nil {
+			return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+		}
+	}
+	resourceDir := filepath.Join(baseObjDir, resourceDirName(co.GVR))
+	err := os.MkdirAll(resourceDir, 0755)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrDownloadFailed, resourceDir, err)
+	}
+	filename := filepath.Join(resourceDir, objFileName(co.Obj.GetNamespace(), co.Obj.GetName()))
+	err = writeObjToYAMLFile(filename, co.Obj)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+	}
+	slog.Debug("Downloaded object", "filename", filename)
+	return nil
+}
+
+func closureKey(gvk schema.GroupVersionKind, ns, name string) string {
+	return gvk.GroupKind().String() + "/" + ns + "/" + name
+}
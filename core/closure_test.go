@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"github.com/alitto/pond/v2"
+	"github.com/elankath/kcpcl/api"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestDownloadClosure(t *testing.T) {
+	ctx := context.Background()
+	replicaSetsGVR := appsv1.SchemeGroupVersion.WithResource("replicasets")
+	deploymentsGVR := appsv1.SchemeGroupVersion.WithResource("deployments")
+
+	selected := newPod("a", "n1", "")
+	selected.SetLabels(map[string]string{"app": "a"})
+	selected.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", UID: "1"}})
+	selected.Object["spec"].(map[string]any)["volumes"] = []any{
+		map[string]any{"name": "config", "configMap": map[string]any{"name": "cm"}},
+		map[string]any{"name": "creds", "secret": map[string]any{"secretName": "missing"}},
+	}
+	unselected := newPod("b", "n1", "")
+	unselected.Object["spec"].(map[string]any)["volumes"] = []any{
+		map[string]any{"name": "config", "configMap": map[string]any{"name": "other"}},
+	}
+	// the owner references of rs and d form a cycle that the closure must not follow endlessly
+	rs := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "apps/v1", "kind": "ReplicaSet"}}
+	rs.SetNamespace("default")
+	rs.SetName("rs")
+	rs.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "d", UID: "2"}})
+	d := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "apps/v1", "kind": "Deployment"}}
+	d.SetNamespace("default")
+	d.SetName("d")
+	d.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs", UID: "1"}})
+	csiNode := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "storage.k8s.io/v1", "kind": "CSINode"}}
+	csiNode.SetName("n1")
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR:     "PodList",
+		nodesGVR:    "NodeList",
+		csiNodesGVR: "CSINodeList",
+	}, selected, unselected, rs, d, newObj("Namespace", "", "default"), newObj("ServiceAccount", "default", "default"),
+		newObj("ConfigMap", "default", "cm"), newObj("ConfigMap", "default", "other"), newNode("n1"), csiNode)
+	var mu sync.Mutex
+	gets := make(map[string]int)
+	dynamicClient.PrependReactor("get", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		gets[action.GetResource().Resource+"/"+action.(clienttesting.GetAction).GetName()]++
+		return false, nil, nil
+	})
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+			{Name: "namespaces", Kind: "Namespace"},
+			{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+			{Name: "secrets", Kind: "Secret", Namespaced: true},
+			{Name: "nodes", Kind: "Node"},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true},
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+		}},
+		{GroupVersion: "storage.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "csinodes", Kind: "CSINode"},
+		}},
+	}}}
+	g := &GardenerShootCopier{dynamicClient: dynamicClient, discoveryClient: discoveryClient, pool: pond.NewPool(4)}
+
+	baseObjDir := t.TempDir()
+	err := g.DownloadClosure(ctx, baseObjDir, []schema.GroupVersionResource{podsGVR}, api.ObjSelector{LabelSelector: "app=a", Namespace: "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err = filepath.WalkDir(baseObjDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(baseObjDir, path)
+		got = append(got, rel)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for gvr, obj := range map[schema.GroupVersionResource]*unstructured.Unstructured{
+		podsGVR:        selected,
+		replicaSetsGVR: rs,
+		deploymentsGVR: d,
+		corev1.SchemeGroupVersion.WithResource("namespaces"):      newObj("Namespace", "", "default"),
+		corev1.SchemeGroupVersion.WithResource("serviceaccounts"): newObj("ServiceAccount", "default", "default"),
+		corev1.SchemeGroupVersion.WithResource("configmaps"):      newObj("ConfigMap", "default", "cm"),
+		nodesGVR:    newNode("n1"),
+		csiNodesGVR: csiNode,
+	} {
+		want = append(want, filepath.Join(resourceDirName(gvr), objFileName(obj.GetNamespace(), obj.GetName())))
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("unexpected closure files:\n got %v\nwant %v", got, want)
+	}
+	for key, n := range gets {
+		if n != 1 {
+			t.Errorf("expected %s to be fetched once, got %d", key, n)
+		}
+	}
+	if gets["secrets/missing"] != 1 {
+		t.Errorf("expected missing secret to be looked up and skipped, got %v", gets)
+	}
+}
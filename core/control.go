@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io/fs"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/restmapper"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ControlDirName is the name of the subtree of the obj dir holding the objects of the shoot control-plane. It has the
+// same layout as the obj dir itself.
+var ControlDirName = "control"
+
+var (
+	machineDeploymentsGVR = schema.GroupVersionResource{Group: "machine.sapcloud.io", Version: "v1alpha1", Resource: "machinedeployments"}
+	machineClassesGVR     = schema.GroupVersionResource{Group: "machine.sapcloud.io", Version: "v1alpha1", Resource: "machineclasses"}
+	machinesGVR           = schema.GroupVersionResource{Group: "machine.sapcloud.io", Version: "v1alpha1", Resource: "machines"}
+)
+
+// controlResource is a resource downloaded from the control-plane namespace. Only objects whose name starts with
+// namePrefix are downloaded.
+type controlResource struct {
+	GVR        schema.GroupVersionResource
+	NamePrefix string
+}
+
+const clusterAutoscalerName = "cluster-autoscaler"
+
+var controlResources = []controlResource{
+	{GVR: machineDeploymentsGVR},
+	{GVR: machineClassesGVR},
+	{GVR: machinesGVR},
+	{GVR: appsv1.SchemeGroupVersion.WithResource("deployments"), NamePrefix: clusterAutoscalerName},
+	{GVR: corev1.SchemeGroupVersion.WithResource("configmaps"), NamePrefix: clusterAutoscalerName},
+}
+
+func (g *GardenerShootCopier) DownloadControlObjects(ctx context.Context, baseObjDir string) error {
+	if g.controlDynamicClient == nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, api.ErrMissingControlKubeConfig)
+	}
+	ns := g.controlNamespace()
+	if ns == "" {
+		return fmt.Errorf("%w: cannot determine control-plane namespace of shoot", api.ErrDownloadFailed)
+	}
+	slog.Info("Downloading control-plane objects", "namespace", ns)
+	apiGroupResources, err := restmapper.GetAPIGroupResources(g.controlDiscoveryClient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch API group resources of control cluster: %w", api.ErrDiscovery, err)
+	}
+	gvrList := make([]schema.GroupVersionResource, 0, len(controlResources))
+	for _, cr := range controlResources {
+		gvrList = append(gvrList, cr.GVR)
+	}
+	err = ValidateGVRs(apiGroupResources, gvrList)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+	}
+
+	controlDir := filepath.Join(baseObjDir, ControlDirName)
+	err = os.MkdirAll(controlDir, 0755)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrDownloadFailed, controlDir, err)
+	}
+	state, err := newDownloadState(controlDir, g.cfg.Incremental)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+	}
+	if g.shootInfo != nil {
+		state.recordShoot(g.shootInfo.Coords, g.shootInfo.Seed)
+	}
+	for _, cr := range controlResources {
+		resourceDir := filepath.Join(controlDir, resourceDirName(cr.GVR))
+		err = os.MkdirAll(resourceDir, 0755)
+		if err != nil {
+			return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrDownloadFailed, resourceDir, err)
+		}
+		objList, retries, err := g.listWithRetry(ctx, g.controlDynamicClient.Resource(cr.GVR).Namespace(ns), metav1.ListOptions{})
+		state.addRetries(retries)
+		if err != nil {
+			return fmt.Errorf("%w: failed to list objects for gvr %q in control namespace %q: %w", api.ErrDownloadFailed, cr.GVR, ns, err)
+		}
+		if cr.NamePrefix != "" {
+			objList.Items = filterByNamePrefix(objList.Items, cr.NamePrefix)
+		}
+		err = writeObjectList(objList, cr.GVR, resourceDir, ns, state)
+		if err != nil {
+			return err
+		}
+	}
+	return state.finish(gvrList)
+}
+
+// controlNamespace returns the control-plane namespace of the shoot from the config, the resolved shoot or else the
+// shoot coordinates.
+func (g *GardenerShootCopier) controlNamespace() string {
+	if g.cfg.ControlNamespace != "" {
+		return g.cfg.ControlNamespace
+	}
+	if g.shootInfo != nil && g.shootInfo.Namespace != "" {
+		return g.shootInfo.Namespace
+	}
+	return ShootControlNamespace(g.cfg.Shoot)
+}
+
+// ShootControlNamespace returns the conventional control-plane namespace of the shoot with the given coordinates in
+// its seed or empty if the coordinates are incomplete.
+func ShootControlNamespace(coords api.ShootCoords) string {
+	if coords.Project == "" || coords.Name == "" {
+		return ""
+	}
+	return "shoot--" + coords.Project + "--" + coords.Name
+}
+
+func filterByNamePrefix(objs []unstructured.Unstructured, prefix string) []unstructured.Unstructured {
+	var filtered []unstructured.Unstructured
+	for _, obj := range objs {
+		if strings.HasPrefix(obj.GetName(), prefix) {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+// isSubtreeDir returns whether the given walked path is the control or node templates subtree of the obj dir. Objects
+// in these subtrees are not part of the cluster snapshot itself.
+func isSubtreeDir(baseObjDir string, path string, e fs.DirEntry) bool {
+	if !e.IsDir() || filepath.Dir(path) != filepath.Clean(baseObjDir) {
+		return false
+	}
+	return e.Name() == ControlDirName || e.Name() == NodeTemplatesDirName
+}
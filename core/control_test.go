@@ -0,0 +1,47 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubtreeDirsSkippedWhenLoadingObjDir(t *testing.T) {
+	baseObjDir := t.TempDir()
+	writeObj(t, baseObjDir, corev1.SchemeGroupVersion.WithResource("pods"), &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": "p1", "namespace": "default"},
+	}})
+	writeObj(t, filepath.Join(baseObjDir, ControlDirName), machinesGVR, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "machine.sapcloud.io/v1alpha1",
+		"kind":       "Machine",
+		"metadata":   map[string]any{"name": "m1", "namespace": "shoot--dev--aw"},
+	}})
+	writeObj(t, filepath.Join(baseObjDir, NodeTemplatesDirName), nodesGVR, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Node",
+		"metadata":   map[string]any{"name": "template-a"},
+	}})
+	if err := GenKWOKConfiguration(filepath.Join(baseObjDir, "kwok-config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	objs, _, err := loadObjsByID(baseObjDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 {
+		t.Errorf("expected only the data plane object to be loaded, without subtrees and files in the obj dir itself, got %v", objs)
+	}
+	controlObjs, _, err := loadObjsByID(filepath.Join(baseObjDir, ControlDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := api.ObjID{GVR: api.FormatGVR(machinesGVR), Namespace: "shoot--dev--aw", Name: "m1"}
+	if _, ok := controlObjs[id]; !ok || len(controlObjs) != 1 {
+		t.Errorf("expected control object %s to be loaded, got %v", id, controlObjs)
+	}
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/restmapper"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// copyPageSize is the number of objects listed from the source per request. Only a single page per GVR is held in
+// memory and uploaded before the next page is listed.
+var copyPageSize int64 = 500
+
+func (g *GardenerShootCopier) CopyObjects(ctx context.Context, gvrList []schema.GroupVersionResource, teeObjDir string) error {
+	return g.copyObjects(ctx, gvrList, teeObjDir, nil)
+}
+
+// copyObjects copies the objects of the given GVRs in kind priority order. If guard is not nil, only the objects
+// admitted by it are copied.
+func (g *GardenerShootCopier) copyObjects(ctx context.Context, gvrList []schema.GroupVersionResource, teeObjDir string, guard *shootCopyGuard) error {
+	if g.targetDynamicClient == nil {
+		return fmt.Errorf("%w: %w", api.ErrCopyFailed, api.ErrMissingTargetKubeConfig)
+	}
+	begin := time.Now()
+	sourceGroupResources, err := restmapper.GetAPIGroupResources(g.discoveryClient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch source API group resources: %w", api.ErrDiscovery, err)
+	}
+	err = ValidateGVRs(sourceGroupResources, gvrList)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+	}
+	targetGroupResources, err := restmapper.GetAPIGroupResources(g.targetDiscoveryClient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch target API group resources: %w", api.ErrDiscovery, err)
+	}
+	sourceMapper := restmapper.NewDiscoveryRESTMapper(sourceGroupResources)
+	targetMapper := restmapper.NewDiscoveryRESTMapper(targetGroupResources)
+	apiResourcesByKind := make(map[string]metav1.APIResource)
+	for _, ar := range toAPIResources(targetGroupResources) {
+		apiResourcesByKind[ar.Kind] = ar
+	}
+
+	type kindGVR struct {
+		gvr      schema.GroupVersionResource
+		kind     string
+		priority int
+	}
+	kindGVRs := make([]kindGVR, 0, len(gvrList))
+	for _, gvr := range gvrList {
+		gvk, err := sourceMapper.KindFor(gvr)
+		if err != nil {
+			return fmt.Errorf("%w: failed to fetch kind for %q: %w", api.ErrDiscovery, gvr, err)
+		}
+		kindGVRs = append(kindGVRs, kindGVR{gvr: gvr, kind: gvk.Kind, priority: getKindPriority(apiResourcesByKind, gvk.Kind)})
+	}
+	slices.SortStableFunc(kindGVRs, func(a, b kindGVR) int {
+		return cmp.Compare(a.priority, b.priority)
+	})
+
+	uploadCounter := &atomic.Uint32{}
+	for _, kg := range kindGVRs {
+		slog.Info("Copying objects.", "gvr", kg.gvr, "kind", kg.kind, "priority", kg.priority)
+		err = g.copyResource(ctx, kg.gvr, targetMapper, uploadCounter, teeObjDir, guard)
+		if err != nil {
+			return err
+		}
+	}
+	slog.Info("CopyObjects time taken", "duration", time.Since(begin), "totalUploadCount", uploadCounter.Load())
+	return nil
+}
+
+// copyResource lists the objects of the given GVR from the source page by page and uploads each page to the target
+// before listing the next.
+func (g *GardenerShootCopier) copyResource(ctx context.Context, gvr schema.GroupVersionResource, targetMapper meta.RESTMapper, uploadCounter *atomic.Uint32, teeObjDir string, guard *shootCopyGuard) error {
+	var resourceDir string
+	if teeObjDir != "" {
+		resourceDir = filepath.Join(teeObjDir, resourceDirName(gvr))
+		err := os.MkdirAll(resourceDir, 0755)
+		if err != nil {
+			return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrCopyFailed, resourceDir, err)
+		}
+	}
+	var uploader *KindUploader
+	listOpts := metav1.ListOptions{Limit: copyPageSize}
+	for page := 0; ; page++ {
+		objList, retries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(gvr), listOpts)
+		if err != nil {
+			return fmt.Errorf("%w: failed to list objects for gvr %q: %w", api.ErrCopyFailed, gvr, err)
+		}
+		if !g.cfg.IncludeSecretData {
+			err = redactSecretList(objList)
+			if err != nil {
+				return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+			}
+		}
+		objs := make([]*unstructured.Unstructured, 0, len(objList.Items))
+		for i := range objList.Items {
+			o := &objList.Items[i]
+			if guard != nil && !guard.admit(gvr, o) {
+				continue
+			}
+			if resourceDir != "" {
+				filename := filepath.Join(resourceDir, objFileName(o.GetNamespace(), o.GetName()))
+				err = writeObjToYAMLFile(filename, o)
+				if err != nil {
+					return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+				}
+			}
+			err = CleanObj(o)
+			if err != nil {
+				return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+			}
+			if g.cfg.DummySecretData {
+				err = GenDummySecretData(o)
+				if err != nil {
+					return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+				}
+			}
+			objs = append(objs, o)
+		}
+		if len(objs) > 0 && uploader == nil {
+			uploader, err = newKindUploader(targetMapper, g.targetDynamicClient, objs[0].GroupVersionKind(), uploadCounter, g.cfg)
+			if err != nil {
+				return fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+			}
+			uploader.limiter = g.limiter
+		}
+		err = g.uploadPage(ctx, uploader, objs)
+		if err != nil {
+			return fmt.Errorf("%w: failed to upload page %d of gvr %q: %w", api.ErrCopyFailed, page, gvr, err)
+		}
+		slog.Info("Copied page.", "gvr", gvr, "page", page, "numObjs", len(objs), "numListRetries", retries, "uploadCounter", uploadCounter.Load())
+		listOpts.Continue = objList.GetContinue()
+		if listOpts.Continue == "" {
+			return nil
+		}
+	}
+}
+
+// uploadPage uploads the given objects concurrently, except pods which are uploaded one by one in creation order
+// like in UploadObjects.
+func (g *GardenerShootCopier) uploadPage(ctx context.Context, uploader *KindUploader, objs []*unstructured.Unstructured) error {
+	if len(objs) == 0 {
+		return nil
+	}
+	slices.SortFunc(objs, func(a, b *unstructured.Unstructured) int {
+		return a.GetCreationTimestamp().Compare(b.GetCreationTimestamp().Time)
+	})
+	if uploader.GVK.Kind == "Pod" {
+		for _, o := range objs {
+			err := uploader.Upload(ctx, o)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	taskGroup := g.pool.NewGroupContext(ctx)
+	for _, o := range objs {
+		uploader.UploadAsync(ctx, taskGroup, o)
+	}
+	return taskGroup.Wait()
+}
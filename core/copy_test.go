@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/alitto/pond/v2"
+	"github.com/elankath/kcpcl/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCopyObjects(t *testing.T) {
+	ctx := context.Background()
+	secretsGVR := corev1.SchemeGroupVersion.WithResource("secrets")
+	namespacesGVR := corev1.SchemeGroupVersion.WithResource("namespaces")
+	listKinds := map[schema.GroupVersionResource]string{
+		podsGVR:       "PodList",
+		secretsGVR:    "SecretList",
+		namespacesGVR: "NamespaceList",
+	}
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+	secret := newObj("Secret", "default", "creds")
+	secret.Object["data"] = map[string]any{"password": password}
+	pod := newPod("p", "n1", "")
+	sourceClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, pod, secret, newObj("Namespace", "", "default"))
+	targetClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+			{Name: "secrets", Kind: "Secret", Namespaced: true},
+			{Name: "namespaces", Kind: "Namespace"},
+		}},
+	}}}
+	g := &GardenerShootCopier{dynamicClient: sourceClient, discoveryClient: discoveryClient, pool: pond.NewPool(4)}
+	gvrList := []schema.GroupVersionResource{podsGVR, secretsGVR, namespacesGVR}
+	if err := g.CopyObjects(ctx, gvrList, ""); !errors.Is(err, api.ErrMissingTargetKubeConfig) {
+		t.Errorf("expected error for missing target, got %v", err)
+	}
+
+	g.targetDynamicClient, g.targetDiscoveryClient = targetClient, discoveryClient
+	teeObjDir := t.TempDir()
+	if err := g.CopyObjects(ctx, gvrList, teeObjDir); err != nil {
+		t.Fatal(err)
+	}
+
+	var created []string
+	for _, a := range targetClient.Actions() {
+		if a.GetVerb() == "create" {
+			created = append(created, a.GetResource().Resource)
+		}
+	}
+	if want := []string{"namespaces", "secrets", "pods"}; !slices.Equal(created, want) {
+		t.Errorf("expected objects to be created in kind priority order %v, got %v", want, created)
+	}
+	copied, err := targetClient.Resource(podsGVR).Namespace("default").Get(ctx, "p", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeName, _, _ := unstructured.NestedString(copied.Object, "spec", "nodeName"); nodeName != "" || copied.GetAnnotations()[api.AnnotationOriginalNodeName] != "n1" {
+		t.Errorf("expected copied pod to be unbound and keep its original node in an annotation, got %v", copied.Object)
+	}
+	copied, err = targetClient.Resource(secretsGVR).Namespace("default").Get(ctx, "creds", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _, _ := unstructured.NestedStringMap(copied.Object, "data"); data["password"] == password {
+		t.Errorf("expected copied secret data to be redacted, got %v", data)
+	}
+
+	objs, _, err := loadObjsByID(teeObjDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 3 {
+		t.Errorf("expected all source objects to be saved to the tee obj dir, got %v", objs)
+	}
+	saved, err := LoadObj(filepath.Join(teeObjDir, resourceDirName(podsGVR), objFileName("default", "p")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeName, _, _ := unstructured.NestedString(saved.Object, "spec", "nodeName"); nodeName != "n1" {
+		t.Errorf("expected saved pod to be left as in the source, got %v", saved.Object)
+	}
+}
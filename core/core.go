@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
@@ -40,7 +41,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/kubernetes"
 	"log/slog"
 )
 
@@ -61,30 +61,116 @@ var (
 	APIResourcesFilename = "api-resources.yaml"
 )
 
+// shootKubeConfigExpiration is the validity of the viewer kubeconfig generated when the source cluster is given by its
+// gardener shoot coordinates.
+const shootKubeConfigExpiration = time.Hour
+
+// replaceTimeout is the maximum duration an upload waits for the deletion of an object it replaces.
+const replaceTimeout = 2 * time.Minute
+
 type GardenerShootCopier struct {
-	cfg             api.CopierConfig
-	gardenClient    *kubernetes.Clientset
+	cfg          api.CopierConfig
+	gardenClient dynamic.Interface
+	// shootInfo is only initialized when the source cluster is given by its gardener shoot coordinates.
+	shootInfo       *api.ShootInfo
 	dynamicClient   dynamic.Interface
-	discoveryClient *discovery.DiscoveryClient
-	targetClient    *kubernetes.Clientset
-	pool            pond.Pool
+	discoveryClient discovery.DiscoveryInterface
+	// targetDynamicClient and targetDiscoveryClient are only initialized when api.CopierConfig.TargetKubeConfigPath is set.
+	targetDynamicClient   dynamic.Interface
+	targetDiscoveryClient discovery.DiscoveryInterface
+	// controlDynamicClient and controlDiscoveryClient are only initialized when api.CopierConfig.ControlKubeConfigPath is set.
+	controlDynamicClient   dynamic.Interface
+	controlDiscoveryClient discovery.DiscoveryInterface
+	pool                   pond.Pool
+	// limiter is only initialized when api.CopierConfig.AdaptiveConcurrency is set.
+	limiter *adaptiveLimiter
 }
 
+// NewShootCopierFromConfig creates a ShootCopier for the source cluster given by api.CopierConfig.KubeConfigPath. See
+// NewShootCopierFromConfigWithContext.
 func NewShootCopierFromConfig(copyCfg api.CopierConfig) (copier api.ShootCopier, err error) {
+	return NewShootCopierFromConfigWithContext(context.Background(), copyCfg)
+}
+
+// NewShootCopierFromConfigWithContext creates a ShootCopier for the source cluster given by
+// api.CopierConfig.KubeConfigPath. If no kubeconfig path is given, the source cluster is the shoot given by
+// api.CopierConfig.Shoot which is resolved via the garden cluster and accessed using a short-lived viewer kubeconfig.
+// The given context bounds the requests to the garden cluster.
+func NewShootCopierFromConfigWithContext(ctx context.Context, copyCfg api.CopierConfig) (copier api.ShootCopier, err error) {
 	var gsc GardenerShootCopier
 	gsc.cfg = copyCfg
-	gsc.dynamicClient, gsc.discoveryClient, err = clientutil.CreateDynamicAndDiscoveryClients(copyCfg.KubeConfigPath, copyCfg.PoolSize)
-	if err != nil {
-		err = fmt.Errorf("%w: cannot create kube clients from %q: %w", api.ErrCreateKubeClient, copyCfg.KubeConfigPath, err)
-		return
+	qps, burst := copyCfg.ClientRateLimit()
+	if copyCfg.GardenKubeConfigPath != "" {
+		gsc.gardenClient, err = clientutil.CreateDynamicClient(copyCfg.GardenKubeConfigPath)
+		if err != nil {
+			err = fmt.Errorf("%w: cannot create garden client from %q: %w", api.ErrCreateGardenClient, copyCfg.GardenKubeConfigPath, err)
+			return
+		}
+	}
+	if copyCfg.KubeConfigPath == "" {
+		err = gsc.initShootClients(ctx)
+		if err != nil {
+			return
+		}
+	} else {
+		gsc.dynamicClient, gsc.discoveryClient, err = clientutil.CreateDynamicAndDiscoveryClients(copyCfg.KubeConfigPath, qps, burst)
+		if err != nil {
+			err = fmt.Errorf("%w: cannot create kube clients from %q: %w", api.ErrCreateKubeClient, copyCfg.KubeConfigPath, err)
+			return
+		}
+	}
+	if copyCfg.TargetKubeConfigPath != "" {
+		gsc.targetDynamicClient, gsc.targetDiscoveryClient, err = clientutil.CreateDynamicAndDiscoveryClients(copyCfg.TargetKubeConfigPath, qps, burst)
+		if err != nil {
+			err = fmt.Errorf("%w: cannot create kube clients from %q: %w", api.ErrCreateKubeClient, copyCfg.TargetKubeConfigPath, err)
+			return
+		}
+	} else if copyCfg.TargetShoot.Name != "" {
+		err = gsc.initTargetShootClients(ctx)
+		if err != nil {
+			return
+		}
+	}
+	if copyCfg.ControlKubeConfigPath != "" {
+		gsc.controlDynamicClient, gsc.controlDiscoveryClient, err = clientutil.CreateDynamicAndDiscoveryClients(copyCfg.ControlKubeConfigPath, qps, burst)
+		if err != nil {
+			err = fmt.Errorf("%w: cannot create kube clients from %q: %w", api.ErrCreateKubeClient, copyCfg.ControlKubeConfigPath, err)
+			return
+		}
 	}
 	gsc.pool = pond.NewPool(copyCfg.PoolSize)
+	if copyCfg.AdaptiveConcurrency {
+		gsc.limiter = newAdaptiveLimiter(copyCfg.PoolSize)
+	}
 	copier = &gsc
 	return
 }
 
+func (g *GardenerShootCopier) initShootClients(ctx context.Context) (err error) {
+	coords := g.cfg.Shoot
+	if g.gardenClient == nil {
+		return fmt.Errorf("%w: %w", api.ErrCreateGardenClient, api.ErrMissingKubeConfig)
+	}
+	info, err := ResolveShoot(ctx, g.gardenClient, coords)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrCreateKubeClient, err)
+	}
+	kubeConfig, _, err := GenShootKubeConfig(ctx, g.gardenClient, coords, ViewerAccess, shootKubeConfigExpiration)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrCreateKubeClient, err)
+	}
+	qps, burst := g.cfg.ClientRateLimit()
+	g.dynamicClient, g.discoveryClient, err = clientutil.CreateDynamicAndDiscoveryClientsFromKubeConfig(kubeConfig, qps, burst)
+	if err != nil {
+		return fmt.Errorf("%w: cannot create kube clients for shoot %s: %w", api.ErrCreateKubeClient, coords, err)
+	}
+	g.shootInfo = &info
+	return
+}
+
 func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir string, gvrList []schema.GroupVersionResource) error {
 	slog.Info("Downloading objects")
+	begin := time.Now()
 	apiGroupResources, err := restmapper.GetAPIGroupResources(g.discoveryClient)
 	if err != nil {
 		return fmt.Errorf("%w: failed to fetch API group resources: %w", api.ErrDiscovery, err)
@@ -110,6 +196,13 @@ func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir st
 		return err
 	}
 
+	state, err := newDownloadState(baseObjDir, g.cfg.Incremental)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+	}
+	if g.shootInfo != nil {
+		state.recordShoot(g.shootInfo.Coords, g.shootInfo.Seed)
+	}
 	taskGroup := g.pool.NewGroupContext(ctx)
 
 	var isNamespaced bool
@@ -118,7 +211,7 @@ func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir st
 		if err != nil {
 			return fmt.Errorf("%w: %w", api.ErrDiscovery, err)
 		}
-		resourceDir := filepath.Join(baseObjDir, gvr.Group+"-"+gvr.Version+"-"+gvr.Resource)
+		resourceDir := filepath.Join(baseObjDir, resourceDirName(gvr))
 		err = os.MkdirAll(resourceDir, 0755)
 		if err != nil {
 			return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrDownloadFailed, resourceDir, err)
@@ -127,12 +220,19 @@ func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir st
 		if isNamespaced {
 			for _, ns := range allNamespaces {
 				taskGroup.SubmitErr(func() error {
-					objList, err := g.dynamicClient.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+					objList, retries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(gvr).Namespace(ns), metav1.ListOptions{})
+					state.addRetries(retries)
 					if err != nil {
 						err = fmt.Errorf("%w: failed to list objects for gvr %q in namespace %q: %w", api.ErrDownloadFailed, gvr, ns, err)
 						return err
 					}
-					err = writeObjectList(objList, resourceDir, ns)
+					if !g.cfg.IncludeSecretData {
+						err = redactSecretList(objList)
+						if err != nil {
+							return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+						}
+					}
+					err = writeObjectList(objList, gvr, resourceDir, ns, state)
 					if err != nil {
 						return err
 					}
@@ -141,12 +241,19 @@ func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir st
 			}
 		} else {
 			taskGroup.SubmitErr(func() error {
-				objList, err := g.dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+				objList, retries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(gvr), metav1.ListOptions{})
+				state.addRetries(retries)
 				if err != nil {
 					err = fmt.Errorf("%w: failed to list objects for gvr %q: %w", api.ErrDownloadFailed, gvr, err)
 					return err
 				}
-				err = writeObjectList(objList, resourceDir, "")
+				if !g.cfg.IncludeSecretData {
+					err = redactSecretList(objList)
+					if err != nil {
+						return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
+					}
+				}
+				err = writeObjectList(objList, gvr, resourceDir, "", state)
 				if err != nil {
 					return err
 				}
@@ -154,20 +261,49 @@ func (g *GardenerShootCopier) DownloadObjects(ctx context.Context, baseObjDir st
 			})
 		}
 	}
-	return taskGroup.Wait()
+	err = taskGroup.Wait()
+	if err != nil {
+		return err
+	}
+	numObjs, numRetries := state.counts()
+	duration := time.Since(begin)
+	slog.Info("Downloaded objects.", "numObjs", numObjs, "numRetries", numRetries, "duration", duration,
+		"throughput", fmt.Sprintf("%.1f/s", float64(numObjs)/duration.Seconds()), "concurrency", g.limiter.concurrencyStats())
+	return state.finish(gvrList)
 }
 
-func (g *GardenerShootCopier) UploadObjects(ctx context.Context, baseObjDir string) (err error) {
+func (g *GardenerShootCopier) UploadObjects(ctx context.Context, baseObjDir string) error {
+	_, err := g.UploadObjectsWithReport(ctx, baseObjDir)
+	return err
+}
+
+func (g *GardenerShootCopier) UploadObjectsWithReport(ctx context.Context, baseObjDir string) (report api.UploadReport, err error) {
 	begin := time.Now()
+	recorder := &uploadRecorder{}
 
 	allObjs, err := loadObjects(baseObjDir, g.pool.NewGroupContext(ctx))
 	if err != nil {
 		err = fmt.Errorf("%w: failed to load objects: %w", api.ErrUploadFailed, err)
 		return
 	}
+	if g.cfg.DummySecretData {
+		for _, o := range allObjs {
+			err = GenDummySecretData(o)
+			if err != nil {
+				err = fmt.Errorf("%w: %w", api.ErrUploadFailed, err)
+				return
+			}
+		}
+	}
+	if g.cfg.KWOK {
+		for _, o := range allObjs {
+			MarkKWOKNode(o)
+		}
+	}
 	apiGroupResources, err := restmapper.GetAPIGroupResources(g.discoveryClient)
 	if err != nil {
-		return fmt.Errorf("%w: failed to fetch API group resources: %w", api.ErrDiscovery, err)
+		err = fmt.Errorf("%w: failed to fetch API group resources: %w", api.ErrDiscovery, err)
+		return
 	}
 
 	objChunks := chunkObjectsByPriority(allObjs, toAPIResources(apiGroupResources))
@@ -182,21 +318,12 @@ func (g *GardenerShootCopier) UploadObjects(ctx context.Context, baseObjDir stri
 		if ok {
 			continue
 		}
-		gvk := o.GroupVersionKind()
-		var restMapping *meta.RESTMapping
-		restMapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		uploader, err = newKindUploader(mapper, g.dynamicClient, o.GroupVersionKind(), uploadCounter, g.cfg)
 		if err != nil {
-			err = fmt.Errorf("%w: failed to fetch REST mapping for %q: %w", api.ErrDiscovery, gvk, err)
 			return
 		}
-		gvr := restMapping.Resource
-		resourceFacade := g.dynamicClient.Resource(gvr)
-		uploader = &KindUploader{
-			GVK:            gvk,
-			GVR:            gvr,
-			ResourceFacade: resourceFacade,
-			Counter:        uploadCounter,
-		}
+		uploader.recorder = recorder
+		uploader.limiter = g.limiter
 		kindUploaders[oKind] = uploader
 	}
 
@@ -211,12 +338,20 @@ func (g *GardenerShootCopier) UploadObjects(ctx context.Context, baseObjDir stri
 			if o.GetKind() == "Pod" {
 				pods = append(pods, o)
 			} else {
-				u.UploadAsync(ctx, chunkTask, o)
+				chunkTask.Submit(func() {
+					if err := u.Upload(ctx, o); err != nil {
+						slog.Error("Cannot upload object.", "error", err)
+					}
+				})
 			}
 		}
-		err := chunkTask.Wait()
+		err = chunkTask.Wait()
+		if err == nil {
+			err = g.checkUploadFailures(recorder)
+		}
 		if err != nil {
-			return fmt.Errorf("%w: failed to upload chunk %d: %w", api.ErrUploadFailed, i, err)
+			report, err = g.finishUpload(begin, recorder, fmt.Errorf("%w: failed to upload chunk %d: %w", api.ErrUploadFailed, i, err))
+			return
 		}
 		slog.Info("completed upload chunk", "chunkIndex", i, "numObjs", len(objs), "uploadCounter", uploadCounter.Load())
 	}
@@ -230,12 +365,47 @@ func (g *GardenerShootCopier) UploadObjects(ctx context.Context, baseObjDir stri
 		u := kindUploaders[p.GetKind()]
 		err = u.Upload(ctx, p)
 		if err != nil {
-			return fmt.Errorf("%w: failed to upload object %q, index: %d: %w", api.ErrUploadFailed, podKey, err)
+			slog.Error("Cannot upload pod.", "index", i, "error", err)
+			if err = g.checkUploadFailures(recorder); err != nil {
+				report, err = g.finishUpload(begin, recorder, fmt.Errorf("%w: failed to upload object %q, index: %d: %w", api.ErrUploadFailed, podKey, i, err))
+				return
+			}
 		}
 	}
 
 	end := time.Now()
 	slog.Info("UploadObjects time taken", "duration", end.Sub(begin), "totalUploadCount", uploadCounter.Load())
+	return g.finishUpload(begin, recorder, nil)
+}
+
+// checkUploadFailures returns an error if more objects than api.CopierConfig.MaxUploadFailures failed to upload.
+func (g *GardenerShootCopier) checkUploadFailures(recorder *uploadRecorder) error {
+	if n := recorder.failures(); n > g.cfg.MaxUploadFailures {
+		return fmt.Errorf("%d objects failed to upload, exceeding the maximum of %d", n, g.cfg.MaxUploadFailures)
+	}
+	return nil
+}
+
+// finishUpload builds the report of the upload started at begin and writes it to api.CopierConfig.UploadReportPath.
+// The given upload error takes precedence over errors writing the report.
+func (g *GardenerShootCopier) finishUpload(begin time.Time, recorder *uploadRecorder, uploadErr error) (report api.UploadReport, err error) {
+	report = recorder.report()
+	report.Duration = time.Since(begin)
+	if secs := report.Duration.Seconds(); secs > 0 {
+		report.Throughput = float64(report.NumObjs) / secs
+	}
+	report.Concurrency = g.limiter.concurrencyStats()
+	slog.Info("Upload outcome.", "numObjs", report.NumObjs, "numCreated", report.NumCreated, "numSkippedExists", report.NumSkippedExists,
+		"numForbidden", report.NumForbidden, "numFailed", report.NumFailed, "throughput", fmt.Sprintf("%.1f/s", report.Throughput))
+	if g.cfg.UploadReportPath != "" {
+		err = saveUploadReport(g.cfg.UploadReportPath, report)
+		if err == nil {
+			slog.Info("Wrote upload report.", "path", g.cfg.UploadReportPath)
+		}
+	}
+	if uploadErr != nil {
+		err = uploadErr
+	}
 	return
 }
 
@@ -244,6 +414,28 @@ type KindUploader struct {
 	GVR            schema.GroupVersionResource
 	ResourceFacade dynamic.NamespaceableResourceInterface
 	Counter        *atomic.Uint32
+	Policy         api.UploadPolicy
+	Retry          api.RetryPolicy
+	// recorder records the outcome of every uploaded object if set.
+	recorder *uploadRecorder
+	// limiter adapts the number of concurrent create requests if set.
+	limiter *adaptiveLimiter
+}
+
+func newKindUploader(mapper meta.RESTMapper, dynamicClient dynamic.Interface, gvk schema.GroupVersionKind, counter *atomic.Uint32, cfg api.CopierConfig) (*KindUploader, error) {
+	restMapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch REST mapping for %q: %w", api.ErrDiscovery, gvk, err)
+	}
+	gvr := restMapping.Resource
+	return &KindUploader{
+		GVK:            gvk,
+		GVR:            gvr,
+		ResourceFacade: dynamicClient.Resource(gvr),
+		Counter:        counter,
+		Policy:         cfg.UploadPolicy,
+		Retry:          cfg.Retry,
+	}, nil
 }
 
 func (u *KindUploader) UploadAsync(ctx context.Context, taskGroup pond.TaskGroup, obj *unstructured.Unstructured) {
@@ -253,52 +445,161 @@ func (u *KindUploader) UploadAsync(ctx context.Context, taskGroup pond.TaskGroup
 	})
 }
 
+// Upload creates the given object. Objects that already exist or whose creation is forbidden are handled according to
+// the exists and forbidden policy of the kind.
 func (u *KindUploader) Upload(ctx context.Context, obj *unstructured.Unstructured) error {
 	slog.Debug("Commencing upload for obj", "kind", u.GVK.Kind, "objName", obj.GetName(), "objNamespace", obj.GetNamespace())
-	var ri dynamic.ResourceInterface = u.ResourceFacade
-	if obj.GetNamespace() != "" {
-		ri = u.ResourceFacade.Namespace(obj.GetNamespace())
+	begin := time.Now()
+	outcome, retries, err := u.upload(ctx, obj)
+	if u.recorder != nil {
+		u.recorder.record(u.GVR, obj, outcome, retries, err, time.Since(begin))
 	}
-	createdObj, err := ri.Create(ctx, obj, metav1.CreateOptions{})
-	if err != nil {
-		if errors.IsAlreadyExists(err) {
-			slog.Warn("object already exists, skipping upload.", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
-			return nil
-		}
-		if errors.IsForbidden(err) {
-			slog.Warn("object creation forbidden.", "name", obj.GetName(), "namespace", obj.GetNamespace(), "error", err)
-			return nil
-		}
-		err = fmt.Errorf("failed to create obj of kind %q, name %q and namespace %q: %w",
-			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	switch outcome {
+	case api.UploadFailed:
 		return err
+	case api.UploadSkippedExists:
+		slog.Warn("object already exists, skipping upload.", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	case api.UploadForbidden:
+		slog.Warn("object upload forbidden, skipping upload.", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace(), "error", err)
+		return nil
 	}
 	if u.Counter.Load()%3000 == 0 {
-		slog.Info("object created", "uploadCount", u.Counter.Load(), "kind", createdObj.GetKind(), "name", createdObj.GetName(), "namespace", createdObj.GetNamespace())
+		slog.Info("object "+string(outcome), "uploadCount", u.Counter.Load(), "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 	} else {
-		slog.Debug("object created", "uploadCount", u.Counter.Load(), "kind", createdObj.GetKind(), "name", createdObj.GetName(), "namespace", createdObj.GetNamespace())
+		slog.Debug("object "+string(outcome), "uploadCount", u.Counter.Load(), "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
 	}
 	u.Counter.Add(1)
 	return nil
 }
 
-func loadObjects(baseObjDir string, loadTaskGroup pond.TaskGroup) ([]*unstructured.Unstructured, error) {
-	slog.Info("Loading objects.", "baseObjDir", baseObjDir)
-	objCount := 0
-	var loadMutex sync.Mutex
-	var objs = make([]*unstructured.Unstructured, 0, 3000)
-	err := filepath.WalkDir(baseObjDir, func(path string, e fs.DirEntry, err error) error {
+// upload creates the given object applying the upload and retry policies and returns the outcome along with the number
+// of retries of the create request. Only creates that were certainly not processed are retried, see isUnprocessed.
+// Forbidden errors of the create as well as of the update or replace of an existing object are handled according to
+// the forbidden policy. The returned error is the cause of skipped outcomes as well.
+func (u *KindUploader) upload(ctx context.Context, obj *unstructured.Unstructured) (outcome api.UploadOutcome, retries int, err error) {
+	retries, err = retry(ctx, u.Retry, "create", isUnprocessed, func() error {
+		if err := u.limiter.acquire(ctx); err != nil {
+			return err
+		}
+		begin := time.Now()
+		_, err := u.resourceInterface(obj).Create(ctx, obj, metav1.CreateOptions{})
+		u.limiter.release(time.Since(begin), err)
+		return err
+	})
+	outcome = api.UploadCreated
+	if errors.IsAlreadyExists(err) {
+		switch u.Policy.ExistsPolicyFor(u.GVK.Kind) {
+		case api.ExistsFail:
+		case api.ExistsUpdate:
+			outcome, err = api.UploadUpdated, u.Apply(ctx, obj)
+		case api.ExistsReplace:
+			outcome, err = api.UploadReplaced, u.replace(ctx, obj)
+		default:
+			return api.UploadSkippedExists, retries, err
+		}
+	}
+	switch {
+	case err == nil:
+		return outcome, retries, nil
+	case errors.IsForbidden(err) && u.Policy.ForbiddenPolicyFor(u.GVK.Kind) != api.ForbiddenFail:
+		return api.UploadForbidden, retries, err
+	case outcome != api.UploadCreated:
+		return api.UploadFailed, retries, err
+	}
+	return api.UploadFailed, retries, fmt.Errorf("failed to create obj of kind %q, name %q and namespace %q: %w",
+		obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+}
+
+// replace deletes the existing object, waits till it is gone and creates the given object.
+func (u *KindUploader) replace(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := u.Delete(ctx, obj)
+	if err != nil {
+		return err
+	}
+	ri := u.resourceInterface(obj)
+	err = wait.PollUntilContextTimeout(ctx, time.Second, replaceTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for deletion of obj of kind %q, name %q and namespace %q: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	_, err = ri.Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to recreate obj of kind %q, name %q and namespace %q: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return nil
+}
+
+// Apply creates or updates the given object using server-side apply.
+func (u *KindUploader) Apply(ctx context.Context, obj *unstructured.Unstructured) error {
+	obj = obj.DeepCopy()
+	// uid of the source object acts as a precondition that never matches the target object
+	unstructured.RemoveNestedField(obj.Object, "metadata", "uid")
+	_, err := u.resourceInterface(obj).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: api.ProgramName, Force: true})
+	if err != nil {
+		return fmt.Errorf("failed to apply obj of kind %q, name %q and namespace %q: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	slog.Debug("object applied", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+	return nil
+}
+
+// Delete deletes the given object immediately without grace period. Objects that do not exist are ignored.
+func (u *KindUploader) Delete(ctx context.Context, obj *unstructured.Unstructured) error {
+	var gracePeriod int64
+	err := u.resourceInterface(obj).Delete(ctx, obj.GetName(), metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			slog.Warn("object not found, skipping delete.", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+			return nil
+		}
+		return fmt.Errorf("failed to delete obj of kind %q, name %q and namespace %q: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	slog.Debug("object deleted", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", obj.GetNamespace())
+	return nil
+}
+
+func (u *KindUploader) resourceInterface(obj *unstructured.Unstructured) dynamic.ResourceInterface {
+	if obj.GetNamespace() != "" {
+		return u.ResourceFacade.Namespace(obj.GetNamespace())
+	}
+	return u.ResourceFacade
+}
+
+// walkObjFiles calls fn with the path of every object file in the given obj dir. Files directly in the obj dir, like
+// the KWOK configuration, as well as the control and node templates subtrees are skipped.
+func walkObjFiles(baseObjDir string, fn func(path string) error) error {
+	return filepath.WalkDir(baseObjDir, func(path string, e fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("%w: path error for %q: %w", api.ErrLoadObj, path, err)
 		}
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+		if isSubtreeDir(baseObjDir, path, e) {
+			return filepath.SkipDir
+		}
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") || filepath.Dir(path) == filepath.Clean(baseObjDir) {
 			return nil
 		}
+		return fn(path)
+	})
+}
+
+func loadObjects(baseObjDir string, loadTaskGroup pond.TaskGroup) ([]*unstructured.Unstructured, error) {
+	slog.Info("Loading objects.", "baseObjDir", baseObjDir)
+	objCount := 0
+	var loadMutex sync.Mutex
+	var objs = make([]*unstructured.Unstructured, 0, 3000)
+	err := walkObjFiles(baseObjDir, func(path string) error {
 		// Infer GVR from parent directory
-		resourcesDirName := filepath.Base(filepath.Dir(path))
-		parts := strings.SplitN(resourcesDirName, "-", 3)
-		if len(parts) != 3 {
-			err = fmt.Errorf("%w: invalid object resourcesDirName: %s", api.ErrLoadObj, resourcesDirName)
+		_, err := parseResourceDirName(filepath.Base(filepath.Dir(path)))
+		if err != nil {
 			return err
 		}
 		loadTaskGroup.SubmitErr(func() error {
@@ -329,6 +630,16 @@ func loadObjects(baseObjDir string, loadTaskGroup pond.TaskGroup) ([]*unstructur
 }
 
 func LoadAndCleanObj(objPath string) (obj *unstructured.Unstructured, err error) {
+	obj, err = LoadObj(objPath)
+	if err != nil {
+		return
+	}
+	err = CleanObj(obj)
+	return
+}
+
+// LoadObj loads the object in the YAML file at objPath as-is.
+func LoadObj(objPath string) (obj *unstructured.Unstructured, err error) {
 	data, err := os.ReadFile(objPath)
 	if err != nil {
 		err = fmt.Errorf("%w: failed to read %q: %w", api.ErrLoadObj, objPath, err)
@@ -345,6 +656,13 @@ func LoadAndCleanObj(objPath string) (obj *unstructured.Unstructured, err error)
 		err = fmt.Errorf("%w: failed to unmarshal object in %q: %w", api.ErrLoadObj, objPath, err)
 		return
 	}
+	return
+}
+
+// CleanObj removes the server populated fields of the given object that prevent it from being created in a target
+// cluster and clears the node assignment of pods so that they are scheduled afresh. The original node of a pod is kept
+// in the api.AnnotationOriginalNodeName annotation.
+func CleanObj(obj *unstructured.Unstructured) (err error) {
 	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
 	//unstructured.RemoveNestedField(obj.Object, "metadata", "uid")
 	//unstructured.RemoveNestedField(obj.Object, "metadata", "generation")
@@ -357,6 +675,14 @@ func LoadAndCleanObj(objPath string) (obj *unstructured.Unstructured, err error)
 	}
 	//TODO: Make this configurable via flag
 
+	if nodeName, _, _ := unstructured.NestedString(obj.Object, "spec", "nodeName"); nodeName != "" {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[api.AnnotationOriginalNodeName] = nodeName
+		obj.SetAnnotations(annotations)
+	}
 	err = unstructured.SetNestedField(obj.Object, "", "spec", "nodeName")
 	if err != nil {
 		err = fmt.Errorf("%w: cannot clear spec.nodeName for pod %q: %w", api.ErrLoadObj, obj.GetName(), err)
@@ -371,14 +697,15 @@ func LoadAndCleanObj(objPath string) (obj *unstructured.Unstructured, err error)
 	return
 }
 
-func writeObjectList(objList *unstructured.UnstructuredList, resourceDir string, ns string) (err error) {
+func writeObjectList(objList *unstructured.UnstructuredList, gvr schema.GroupVersionResource, resourceDir string, ns string, state *downloadState) (err error) {
 	var filename string
 	for _, obj := range objList.Items {
-		if ns != "" {
-			filename = filepath.Join(resourceDir, sanitizeFileName(ns+"@"+obj.GetName())+".yaml")
-		} else {
-			filename = filepath.Join(resourceDir, sanitizeFileName(obj.GetName())+".yaml")
+		name := objFileName(ns, obj.GetName())
+		if !state.recordObj(gvr, name, obj.GetResourceVersion()) {
+			slog.Debug("Skipping unchanged object", "name", name, "resourceVersion", obj.GetResourceVersion())
+			continue
 		}
+		filename = filepath.Join(resourceDir, name)
 		err = writeObjToYAMLFile(filename, &obj)
 		if err != nil {
 			return fmt.Errorf("%w: %w", api.ErrDownloadFailed, err)
@@ -409,14 +736,22 @@ func (g *GardenerShootCopier) GetClient() dynamic.Interface {
 	return g.dynamicClient
 }
 
+// GetShootCoordinate returns the coordinates of the source shoot. It is empty unless the source cluster was given by
+// its gardener shoot coordinates.
 func (g *GardenerShootCopier) GetShootCoordinate() api.ShootCoords {
-	//TODO implement me
-	panic("implement me")
+	if g.shootInfo == nil {
+		return api.ShootCoords{}
+	}
+	return g.shootInfo.Coords
 }
 
+// GetSeedCoordinate returns the coordinates of the seed of the source shoot. It is empty unless the source cluster was
+// given by its gardener shoot coordinates.
 func (g *GardenerShootCopier) GetSeedCoordinate() api.ShootCoords {
-	//TODO implement me
-	panic("implement me")
+	if g.shootInfo == nil {
+		return api.ShootCoords{}
+	}
+	return g.shootInfo.Seed
 }
 
 func CreateRegisterScheme() *runtime.Scheme {
@@ -479,6 +814,30 @@ func sanitizeFileName(name string) string {
 	return strings.ReplaceAll(name, "/", "__")
 }
 
+// resourceDirName returns the name of the directory within the obj dir holding objects of the given GVR.
+func resourceDirName(gvr schema.GroupVersionResource) string {
+	return gvr.Group + "-" + gvr.Version + "-" + gvr.Resource
+}
+
+// parseResourceDirName parses the GVR from the name of a resource dir within the obj dir.
+func parseResourceDirName(name string) (gvr schema.GroupVersionResource, err error) {
+	parts := strings.SplitN(name, "-", 3)
+	if len(parts) != 3 {
+		err = fmt.Errorf("%w: invalid object resourcesDirName: %s", api.ErrLoadObj, name)
+		return
+	}
+	gvr = schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}
+	return
+}
+
+// objFileName returns the name of the YAML file within a resource dir holding the object with the given namespace and name.
+func objFileName(ns, name string) string {
+	if ns != "" {
+		return sanitizeFileName(ns+"@"+name) + ".yaml"
+	}
+	return sanitizeFileName(name) + ".yaml"
+}
+
 func toAPIResources(apiGroupResources []*restmapper.APIGroupResources) (allAPIResources []metav1.APIResource) {
 	for _, agr := range apiGroupResources {
 		prefVersion := agr.Group.PreferredVersion.Version
@@ -580,6 +939,10 @@ func chunkObjectsByPriority(objs []*unstructured.Unstructured, apiResources []me
 }
 
 func getObjPriority(apiResourcesByKind map[string]metav1.APIResource, o *unstructured.Unstructured) int {
+	return getKindPriority(apiResourcesByKind, o.GetKind())
+}
+
+func getKindPriority(apiResourcesByKind map[string]metav1.APIResource, kind string) int {
 	leastPriority := math.MaxInt32
 	nsPriority := 0
 	clusterScopedPriority := 1
@@ -588,7 +951,6 @@ func getObjPriority(apiResourcesByKind map[string]metav1.APIResource, o *unstruc
 	saPriority := 5
 	cmPriority := 6
 	namespacesScopedPriority := 7
-	kind := o.GetKind()
 	switch kind {
 	case "Namespace":
 		return nsPriority
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultDiffIgnoreFields are the volatile fields ignored when diffing objects. A '*' matches any map key or list index.
+var DefaultDiffIgnoreFields = []string{
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.managedFields",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.deletionTimestamp",
+	"metadata.selfLink",
+	"status.conditions.*.lastHeartbeatTime",
+	"status.conditions.*.lastTransitionTime",
+	"status.conditions.*.lastProbeTime",
+	"status.conditions.*.lastUpdateTime",
+}
+
+// DiffObjDirs diffs the objects in obj dir A against the objects in obj dir B.
+func DiffObjDirs(dirA, dirB string, ignoreFields []string) (report api.DiffReport, err error) {
+	objsA, _, err := loadObjsByID(dirA)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrDiffFailed, err)
+		return
+	}
+	objsB, _, err := loadObjsByID(dirB)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrDiffFailed, err)
+		return
+	}
+	report = DiffObjs(objsA, objsB, ignoreFields)
+	return
+}
+
+// DiffObjDirWithCluster diffs the objects in obj dir A against the objects of the same GVRs in the cluster of the given client.
+func DiffObjDirWithCluster(ctx context.Context, client dynamic.Interface, dirA string, ignoreFields []string) (report api.DiffReport, err error) {
+	objsA, gvrs, err := loadObjsByID(dirA)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrDiffFailed, err)
+		return
+	}
+	objsB := make(map[api.ObjID]*unstructured.Unstructured)
+	for _, gvr := range gvrs {
+		objList, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if errors.IsNotFound(err) {
+			slog.Warn("Resource not found in cluster.", "gvr", gvr)
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: failed to list objects for gvr %q: %w", api.ErrDiffFailed, gvr, err)
+		}
+		for i := range objList.Items {
+			o := &objList.Items[i]
+			objsB[api.ObjID{GVR: api.FormatGVR(gvr), Namespace: o.GetNamespace(), Name: o.GetName()}] = o
+		}
+	}
+	report = DiffObjs(objsA, objsB, ignoreFields)
+	return
+}
+
+// DiffObjs diffs the objects in A against the objects in B ignoring the given fields.
+func DiffObjs(objsA, objsB map[api.ObjID]*unstructured.Unstructured, ignoreFields []string) (report api.DiffReport) {
+	ignorePatterns := make([][]string, 0, len(ignoreFields))
+	for _, f := range ignoreFields {
+		ignorePatterns = append(ignorePatterns, strings.Split(f, "."))
+	}
+	for _, id := range slices.SortedFunc(maps.Keys(objsA), compareObjIDs) {
+		b, ok := objsB[id]
+		if !ok {
+			report.Removed = append(report.Removed, id)
+			continue
+		}
+		var fields []api.FieldDiff
+		diffValues(nil, objsA[id].Object, b.Object, ignorePatterns, &fields)
+		if len(fields) == 0 {
+			report.NumUnchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, api.ObjDiff{ID: id, Fields: fields})
+	}
+	for _, id := range slices.SortedFunc(maps.Keys(objsB), compareObjIDs) {
+		if _, ok := objsA[id]; !ok {
+			report.Added = append(report.Added, id)
+		}
+	}
+	return
+}
+
+// WriteDiffReport writes the given diff report to w in the given format.
+func WriteDiffReport(w io.Writer, report api.DiffReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		for _, id := range report.Added {
+			_, _ = fmt.Fprintf(w, "+ %s\n", id)
+		}
+		for _, id := range report.Removed {
+			_, _ = fmt.Fprintf(w, "- %s\n", id)
+		}
+		for _, d := range report.Changed {
+			_, _ = fmt.Fprintf(w, "~ %s\n", d.ID)
+			for _, f := range d.Fields {
+				_, _ = fmt.Fprintf(w, "    %s: %s -> %s\n", f.Path, formatDiffValue(f.Old), formatDiffValue(f.New))
+			}
+		}
+		_, err := fmt.Fprintf(w, "Summary: added=%d removed=%d changed=%d unchanged=%d\n",
+			len(report.Added), len(report.Removed), len(report.Changed), report.NumUnchanged)
+		return err
+	})
+}
+
+// loadObjsByID loads the objects in the given obj dir as-is keyed by their ID along with the GVRs of the resource dirs.
+func loadObjsByID(baseObjDir string) (objs map[api.ObjID]*unstructured.Unstructured, gvrs []schema.GroupVersionResource, err error) {
+	objs = make(map[api.ObjID]*unstructured.Unstructured)
+	seen := make(map[schema.GroupVersionResource]struct{})
+	err = walkObjFiles(baseObjDir, func(path string) error {
+		gvr, err := parseResourceDirName(filepath.Base(filepath.Dir(path)))
+		if err != nil {
+			return err
+		}
+		obj, err := LoadObj(path)
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[gvr]; !ok {
+			seen[gvr] = struct{}{}
+			gvrs = append(gvrs, gvr)
+		}
+		objs[api.ObjID{GVR: api.FormatGVR(gvr), Namespace: obj.GetNamespace(), Name: obj.GetName()}] = obj
+		return nil
+	})
+	slog.Info("Loaded objects by ID.", "baseObjDir", baseObjDir, "numObjs", len(objs), "numGVRs", len(gvrs))
+	return
+}
+
+// diffValues recursively appends the differences between a and b at the given path to diffs.
+func diffValues(path []string, a, b any, ignorePatterns [][]string, diffs *[]api.FieldDiff) {
+	if isIgnoredPath(path, ignorePatterns) {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := slices.Collect(maps.Keys(av))
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			diffValues(append(slices.Clip(path), k), av[k], bv[k], ignorePatterns, diffs)
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(av), len(bv)) {
+			var ai, bi any
+			if i < len(av) {
+				ai = av[i]
+			}
+			if i < len(bv) {
+				bi = bv[i]
+			}
+			diffValues(append(slices.Clip(path), "["+strconv.Itoa(i)+"]"), ai, bi, ignorePatterns, diffs)
+		}
+		return
+	}
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	*diffs = append(*diffs, api.FieldDiff{Path: formatFieldPath(path), Old: a, New: b})
+}
+
+func isIgnoredPath(path []string, ignorePatterns [][]string) bool {
+	for _, pattern := range ignorePatterns {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, p := range pattern {
+			if p != "*" && p != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFieldPath(path []string) string {
+	var sb strings.Builder
+	for i, p := range path {
+		if i > 0 && !strings.HasPrefix(p, "[") {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(p)
+	}
+	return sb.String()
+}
+
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func compareObjIDs(a, b api.ObjID) int {
+	return strings.Compare(a.String(), b.String())
+}
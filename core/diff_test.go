@@ -0,0 +1,50 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+// newVersionedPod returns a pod with the given resource version whose Ready condition changes with each version.
+func newVersionedPod(name, nodeName, rv string) *unstructured.Unstructured {
+	pod := newPod(name, nodeName, "")
+	pod.SetResourceVersion(rv)
+	pod.Object["status"] = map[string]any{"conditions": []any{
+		map[string]any{"type": "Ready", "lastTransitionTime": rv},
+	}}
+	return pod
+}
+
+func TestDiffObjs(t *testing.T) {
+	id := func(name string) api.ObjID {
+		return api.ObjID{GVR: "v1/pods", Namespace: "default", Name: name}
+	}
+	objsA := map[api.ObjID]*unstructured.Unstructured{
+		id("same"):    newVersionedPod("same", "n1", "1"),
+		id("moved"):   newVersionedPod("moved", "n1", "1"),
+		id("removed"): newVersionedPod("removed", "n1", "1"),
+	}
+	objsB := map[api.ObjID]*unstructured.Unstructured{
+		id("same"):  newVersionedPod("same", "n1", "2"),
+		id("moved"): newVersionedPod("moved", "n2", "2"),
+		id("added"): newVersionedPod("added", "n1", "2"),
+	}
+	report := DiffObjs(objsA, objsB, DefaultDiffIgnoreFields)
+	if len(report.Added) != 1 || report.Added[0] != id("added") {
+		t.Errorf("unexpected added: %v", report.Added)
+	}
+	if len(report.Removed) != 1 || report.Removed[0] != id("removed") {
+		t.Errorf("unexpected removed: %v", report.Removed)
+	}
+	if report.NumUnchanged != 1 {
+		t.Errorf("expected 1 unchanged object, got %d", report.NumUnchanged)
+	}
+	if len(report.Changed) != 1 {
+		t.Fatalf("expected 1 changed object, got %v", report.Changed)
+	}
+	fields := report.Changed[0].Fields
+	if len(fields) != 1 || fields[0].Path != "spec.nodeName" || fields[0].Old != "n1" || fields[0].New != "n2" {
+		t.Errorf("unexpected field diffs: %+v", fields)
+	}
+}
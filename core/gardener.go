@@ -0,0 +1,156 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// KubeConfigAccess is the access level of a shoot kubeconfig generated via the gardener shoot subresources.
+type KubeConfigAccess string
+
+const (
+	ViewerAccess KubeConfigAccess = "viewer"
+	AdminAccess  KubeConfigAccess = "admin"
+)
+
+var (
+	shootsGVR       = schema.GroupVersionResource{Group: "core.gardener.cloud", Version: "v1beta1", Resource: "shoots"}
+	managedSeedsGVR = schema.GroupVersionResource{Group: "seedmanagement.gardener.cloud", Version: "v1alpha1", Resource: "managedseeds"}
+	// kubeConfigRequestKinds holds the kind of the request for each access level. See:
+	// https://github.com/gardener/gardener/blob/master/docs/usage/shoot/shoot_access.md
+	kubeConfigRequestKinds = map[KubeConfigAccess]string{
+		ViewerAccess: "ViewerKubeconfigRequest",
+		AdminAccess:  "AdminKubeconfigRequest",
+	}
+)
+
+// ResolveShoot fetches the shoot with the given coordinates from the garden cluster and returns its control-plane
+// namespace along with the coordinates of its seed. The seed is only resolved if it is a managed seed, ie a shoot of the
+// 'garden' project in the same landscape, which the caller may read. Otherwise, it is left empty as the seed is only
+// recorded in the download manifest.
+func ResolveShoot(ctx context.Context, gardenClient dynamic.Interface, coords api.ShootCoords) (info api.ShootInfo, err error) {
+	shoot, err := gardenClient.Resource(shootsGVR).Namespace(ProjectNamespace(coords.Project)).Get(ctx, coords.Name, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("%w: cannot get shoot %s: %w", api.ErrResolveShoot, coords, err)
+		return
+	}
+	info.Coords = coords
+	info.Namespace, _, _ = unstructured.NestedString(shoot.Object, "status", "technicalID")
+	seedName, _, _ := unstructured.NestedString(shoot.Object, "status", "seedName")
+	if seedName == "" {
+		seedName, _, _ = unstructured.NestedString(shoot.Object, "spec", "seedName")
+	}
+	if seedName == "" {
+		err = fmt.Errorf("%w: shoot %s is not scheduled to a seed", api.ErrResolveShoot, coords)
+		return
+	}
+	managedSeed, err := gardenClient.Resource(managedSeedsGVR).Namespace(ProjectNamespace("garden")).Get(ctx, seedName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		slog.Warn("Cannot resolve seed of shoot, the seed is not recorded.", "shoot", coords, "seedName", seedName, "error", err)
+		err = nil
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: cannot get managed seed %q of shoot %s: %w", api.ErrResolveShoot, seedName, coords, err)
+		return
+	}
+	seedShootName, _, _ := unstructured.NestedString(managedSeed.Object, "spec", "shoot", "name")
+	if seedShootName == "" {
+		err = fmt.Errorf("%w: managed seed %q of shoot %s has no spec.shoot.name", api.ErrResolveShoot, seedName, coords)
+		return
+	}
+	info.Seed = api.ShootCoords{Landscape: coords.Landscape, Project: "garden", Name: seedShootName}
+	slog.Info("Resolved shoot.", "shoot", coords, "namespace", info.Namespace, "seed", info.Seed)
+	return
+}
+
+// ProjectNamespace returns the namespace of the given gardener project in the garden cluster.
+func ProjectNamespace(project string) string {
+	if project == "garden" {
+		return project
+	}
+	return "garden-" + project
+}
+
+// GenShootKubeConfig creates a short-lived kubeconfig with the given access level for the shoot with the given
+// coordinates using the garden client and returns it along with the name of its current context.
+func GenShootKubeConfig(ctx context.Context, gardenClient dynamic.Interface, coords api.ShootCoords, access KubeConfigAccess, expiration time.Duration) (kubeConfig []byte, contextName string, err error) {
+	kind, ok := kubeConfigRequestKinds[access]
+	if !ok {
+		err = fmt.Errorf("%w: unknown kubeconfig access %q", api.ErrGenKubeConfig, access)
+		return
+	}
+	req := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "authentication.gardener.cloud/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]any{"name": coords.Name},
+		"spec":       map[string]any{"expirationSeconds": int64(expiration.Seconds())},
+	}}
+	ns := ProjectNamespace(coords.Project)
+	subresource := string(access) + "kubeconfig"
+	resp, err := gardenClient.Resource(shootsGVR).Namespace(ns).Create(ctx, req, metav1.CreateOptions{}, subresource)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot create %s for shoot %s: %w", api.ErrGenKubeConfig, subresource, coords, err)
+		return
+	}
+	encoded, found, err := unstructured.NestedString(resp.Object, "status", "kubeconfig")
+	if err != nil || !found {
+		err = fmt.Errorf("%w: %s response for shoot %s has no status.kubeconfig: %v", api.ErrGenKubeConfig, subresource, coords, err)
+		return
+	}
+	kubeConfig, err = base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot decode %s for shoot %s: %w", api.ErrGenKubeConfig, subresource, coords, err)
+		return
+	}
+	cfg, err := clientcmd.Load(kubeConfig)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot load %s for shoot %s: %w", api.ErrGenKubeConfig, subresource, coords, err)
+		return
+	}
+	contextName = cfg.CurrentContext
+	slog.Info("Generated shoot kubeconfig.", "shoot", coords, "access", access, "context", contextName, "expiration", expiration)
+	return
+}
+
+// WriteShootKubeConfig generates a kubeconfig for the shoot with the given coordinates and writes it into genDir as
+// '<context>.yaml', or '<context>-<access>.yaml' for access levels other than viewer. It returns the path of the
+// written kubeconfig.
+func WriteShootKubeConfig(ctx context.Context, gardenClient dynamic.Interface, coords api.ShootCoords, access KubeConfigAccess, expiration time.Duration, genDir string) (path string, err error) {
+	kubeConfig, contextName, err := GenShootKubeConfig(ctx, gardenClient, coords, access, expiration)
+	if err != nil {
+		return
+	}
+	if contextName == "" {
+		contextName = ProjectNamespace(coords.Project) + "--" + coords.Name
+	}
+	err = os.MkdirAll(genDir, 0755)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot create directory %q: %w", api.ErrGenKubeConfig, genDir, err)
+		return
+	}
+	filename := contextName
+	if access != ViewerAccess {
+		filename += "-" + string(access)
+	}
+	path = filepath.Join(genDir, sanitizeFileName(filename)+".yaml")
+	err = os.WriteFile(path, kubeConfig, 0600)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot write kubeconfig %q: %w", api.ErrGenKubeConfig, path, err)
+		return
+	}
+	slog.Info("Wrote shoot kubeconfig.", "path", path)
+	return
+}
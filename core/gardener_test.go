@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const fakeShootKubeConfig = `apiVersion: v1
+kind: Config
+current-context: garden-dev--aw-external
+contexts:
+- name: garden-dev--aw-external
+  context:
+    cluster: garden-dev--aw-external
+    user: garden-dev--aw-external
+clusters:
+- name: garden-dev--aw-external
+  cluster:
+    server: https://api.aw.dev.example.com
+users:
+- name: garden-dev--aw-external
+  user:
+    token: dummy
+`
+
+const fakeShootPath = "/apis/core.gardener.cloud/v1beta1/namespaces/garden-dev/shoots/aw"
+
+// fakeGardenObjs holds the objects served by the fake garden server keyed by their path.
+var fakeGardenObjs = map[string]map[string]any{
+	fakeShootPath: {
+		"apiVersion": "core.gardener.cloud/v1beta1",
+		"kind":       "Shoot",
+		"metadata":   map[string]any{"name": "aw", "namespace": "garden-dev"},
+		"spec":       map[string]any{"seedName": "aws-ha"},
+		"status":     map[string]any{"seedName": "aws-ha", "technicalID": "shoot--dev--aw"},
+	},
+	"/apis/core.gardener.cloud/v1beta1/namespaces/garden-dev/shoots/unmanaged": {
+		"apiVersion": "core.gardener.cloud/v1beta1",
+		"kind":       "Shoot",
+		"metadata":   map[string]any{"name": "unmanaged", "namespace": "garden-dev"},
+		"status":     map[string]any{"seedName": "aws-bare", "technicalID": "shoot--dev--unmanaged"},
+	},
+	"/apis/core.gardener.cloud/v1beta1/namespaces/garden-dev/shoots/restricted": {
+		"apiVersion": "core.gardener.cloud/v1beta1",
+		"kind":       "Shoot",
+		"metadata":   map[string]any{"name": "restricted", "namespace": "garden-dev"},
+		"status":     map[string]any{"seedName": "aws-private", "technicalID": "shoot--dev--restricted"},
+	},
+	"/apis/seedmanagement.gardener.cloud/v1alpha1/namespaces/garden/managedseeds/aws-ha": {
+		"apiVersion": "seedmanagement.gardener.cloud/v1alpha1",
+		"kind":       "ManagedSeed",
+		"metadata":   map[string]any{"name": "aws-ha", "namespace": "garden"},
+		"spec":       map[string]any{"shoot": map[string]any{"name": "aws-ha-shoot"}},
+	},
+}
+
+// fakeForbiddenPath is the path of a managed seed the caller of the fake garden server may not read.
+const fakeForbiddenPath = "/apis/seedmanagement.gardener.cloud/v1alpha1/namespaces/garden/managedseeds/aws-private"
+
+// newFakeGardenServer returns a server that serves fakeGardenObjs and the viewerkubeconfig subresource of shoot 'aw'
+// in project 'dev'.
+func newFakeGardenServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fakeForbiddenPath {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Forbidden", "code": http.StatusForbidden})
+			return
+		}
+		if obj, ok := fakeGardenObjs[r.URL.Path]; ok && r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(obj)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != fakeShootPath+"/viewerkubeconfig" {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var req map[string]any
+		if err = json.Unmarshal(body, &req); err != nil {
+			t.Error(err)
+		}
+		resp := map[string]any{
+			"apiVersion": "authentication.gardener.cloud/v1alpha1",
+			"kind":       req["kind"],
+			"metadata":   map[string]any{"name": "aw"},
+			"spec":       req["spec"],
+			"status":     map[string]any{"kubeconfig": base64.StdEncoding.EncodeToString([]byte(fakeShootKubeConfig))},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestWriteShootKubeConfig(t *testing.T) {
+	server := newFakeGardenServer(t)
+	defer server.Close()
+	gardenClient, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	genDir := t.TempDir()
+	coords := api.ShootCoords{Landscape: "local", Project: "dev", Name: "aw"}
+	path, err := WriteShootKubeConfig(context.Background(), gardenClient, coords, ViewerAccess, time.Hour, genDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(genDir, "garden-dev--aw-external.yaml"); path != expected {
+		t.Errorf("expected kubeconfig path %q, got %q", expected, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != fakeShootKubeConfig {
+		t.Errorf("unexpected kubeconfig content: %s", data)
+	}
+
+	_, err = WriteShootKubeConfig(context.Background(), gardenClient, coords, AdminAccess, time.Hour, genDir)
+	if err == nil {
+		t.Errorf("expected error for subresource not served by fake garden")
+	}
+}
+
+func TestResolveShoot(t *testing.T) {
+	server := newFakeGardenServer(t)
+	defer server.Close()
+	gardenClient, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords := api.ShootCoords{Landscape: "live", Project: "dev", Name: "aw"}
+	info, err := ResolveShoot(context.Background(), gardenClient, coords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := api.ShootInfo{
+		Coords:    coords,
+		Namespace: "shoot--dev--aw",
+		Seed:      api.ShootCoords{Landscape: "live", Project: "garden", Name: "aws-ha-shoot"},
+	}
+	if info != expected {
+		t.Errorf("expected shoot info %+v, got %+v", expected, info)
+	}
+
+	_, err = ResolveShoot(context.Background(), gardenClient, api.ShootCoords{Landscape: "live", Project: "dev", Name: "missing"})
+	if !errors.Is(err, api.ErrResolveShoot) {
+		t.Errorf("expected %v for missing shoot, got %v", api.ErrResolveShoot, err)
+	}
+	for _, name := range []string{"unmanaged", "restricted"} {
+		coords = api.ShootCoords{Landscape: "live", Project: "dev", Name: name}
+		info, err = ResolveShoot(context.Background(), gardenClient, coords)
+		if err != nil {
+			t.Fatalf("expected shoot %s to resolve without its seed, got %v", name, err)
+		}
+		expected = api.ShootInfo{Coords: coords, Namespace: "shoot--dev--" + name}
+		if info != expected {
+			t.Errorf("expected shoot info %+v, got %+v", expected, info)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newObj returns a minimal core/v1 object of the given kind. ns is left out of the metadata if empty.
+func newObj(kind, ns, name string) *unstructured.Unstructured {
+	metadata := map[string]any{"name": name}
+	if ns != "" {
+		metadata["namespace"] = ns
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata":   metadata,
+	}}
+}
+
+// newNode returns a node with 2 cpu, 4Gi memory and 110 pods allocatable and the given taints.
+func newNode(name string, taints ...any) *unstructured.Unstructured {
+	node := newObj("Node", "", name)
+	node.Object["spec"] = map[string]any{"taints": taints}
+	node.Object["status"] = map[string]any{"allocatable": map[string]any{"cpu": "2", "memory": "4Gi", "pods": "110"}}
+	return node
+}
+
+// newPod returns a pod in the default namespace bound to nodeName with a single container requesting cpu. nodeName
+// and cpu are left out of the spec if empty.
+func newPod(name, nodeName, cpu string) *unstructured.Unstructured {
+	container := map[string]any{"name": "c"}
+	if cpu != "" {
+		container["resources"] = map[string]any{"requests": map[string]any{"cpu": cpu}}
+	}
+	spec := map[string]any{"containers": []any{container}}
+	if nodeName != "" {
+		spec["nodeName"] = nodeName
+	}
+	pod := newObj("Pod", "default", name)
+	pod.Object["spec"] = spec
+	return pod
+}
+
+// writeObj writes obj of the given resource to its file below the obj dir dir.
+func writeObj(t *testing.T, dir string, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	t.Helper()
+	resourceDir := filepath.Join(dir, resourceDirName(gvr))
+	if err := os.MkdirAll(resourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeObjToYAMLFile(filepath.Join(resourceDir, objFileName(obj.GetNamespace(), obj.GetName())), obj); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+const noPriorityClass = "<none>"
+
+// InspectObjDir summarizes the objects in the given obj dir. topN is the number of namespaces with most pods reported,
+// all namespaces are reported if it is negative.
+func InspectObjDir(baseObjDir string, topN int) (report api.InspectReport, err error) {
+	objs, _, err := loadObjsByID(baseObjDir)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrInspectFailed, err)
+		return
+	}
+	report.NumObjs = len(objs)
+	report.CountsByGVR = make(map[string]int)
+	report.CountsByNamespace = make(map[string]int)
+	report.PriorityClassCounts = make(map[string]int)
+	report.Pods.Requests = corev1.ResourceList{}
+	report.Pods.Allocatable = corev1.ResourceList{}
+	podsByNamespace := make(map[string]int)
+	nodeGroups := make(map[[2]string]*api.NodeGroupSummary)
+
+	for id, obj := range objs {
+		report.CountsByGVR[id.GVR]++
+		if id.Namespace != "" {
+			report.CountsByNamespace[id.Namespace]++
+		}
+		switch obj.GetKind() {
+		case "Node":
+			var node corev1.Node
+			if err = fromUnstructured(obj, &node); err != nil {
+				err = fmt.Errorf("%w: %w", api.ErrInspectFailed, err)
+				return
+			}
+			key := [2]string{nodeInstanceType(&node), nodeZone(&node)}
+			ng, ok := nodeGroups[key]
+			if !ok {
+				ng = &api.NodeGroupSummary{InstanceType: key[0], Zone: key[1], Capacity: corev1.ResourceList{}, Allocatable: corev1.ResourceList{}}
+				nodeGroups[key] = ng
+			}
+			ng.NumNodes++
+			addResources(ng.Capacity, node.Status.Capacity)
+			addResources(ng.Allocatable, node.Status.Allocatable)
+			addResources(report.Pods.Allocatable, node.Status.Allocatable)
+		case "Pod":
+			var pod corev1.Pod
+			if err = fromUnstructured(obj, &pod); err != nil {
+				err = fmt.Errorf("%w: %w", api.ErrInspectFailed, err)
+				return
+			}
+			report.Pods.NumPods++
+			podsByNamespace[pod.Namespace]++
+			priorityClass := pod.Spec.PriorityClassName
+			if priorityClass == "" {
+				priorityClass = noPriorityClass
+			}
+			report.PriorityClassCounts[priorityClass]++
+			if isPodTerminated(&pod) {
+				continue
+			}
+			if pod.Spec.NodeName != "" {
+				report.Pods.NumBound++
+			} else {
+				report.Pods.NumPending++
+			}
+			addResources(report.Pods.Requests, podRequests(&pod))
+		}
+	}
+
+	for _, key := range slices.SortedFunc(maps.Keys(nodeGroups), func(a, b [2]string) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	}) {
+		report.NodeGroups = append(report.NodeGroups, *nodeGroups[key])
+	}
+	for ns, numPods := range podsByNamespace {
+		report.TopNamespaces = append(report.TopNamespaces, api.NamespaceCount{Namespace: ns, NumPods: numPods})
+	}
+	slices.SortFunc(report.TopNamespaces, func(a, b api.NamespaceCount) int {
+		return cmp.Or(cmp.Compare(b.NumPods, a.NumPods), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	if topN >= 0 && len(report.TopNamespaces) > topN {
+		report.TopNamespaces = report.TopNamespaces[:topN]
+	}
+	return
+}
+
+// WriteInspectReport writes the given inspect report to w in the given format.
+func WriteInspectReport(w io.Writer, report api.InspectReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "TOTAL OBJECTS\t%d\n", report.NumObjs)
+
+		_, _ = fmt.Fprintln(tw, "\nGVR\tCOUNT")
+		writeCounts(tw, report.CountsByGVR)
+
+		_, _ = fmt.Fprintln(tw, "\nNAMESPACE\tCOUNT")
+		writeCounts(tw, report.CountsByNamespace)
+
+		_, _ = fmt.Fprintln(tw, "\nINSTANCE TYPE\tZONE\tNODES\tCPU\tMEMORY\tALLOCATABLE CPU\tALLOCATABLE MEMORY")
+		for _, ng := range report.NodeGroups {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", ng.InstanceType, ng.Zone, ng.NumNodes,
+				ng.Capacity.Cpu(), ng.Capacity.Memory(), ng.Allocatable.Cpu(), ng.Allocatable.Memory())
+		}
+
+		pods := report.Pods
+		_, _ = fmt.Fprintln(tw, "\nPODS\tBOUND\tPENDING")
+		_, _ = fmt.Fprintf(tw, "%d\t%d\t%d\n", pods.NumPods, pods.NumBound, pods.NumPending)
+
+		_, _ = fmt.Fprintln(tw, "\nRESOURCE\tREQUESTED\tALLOCATABLE\tUTILIZATION")
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			req, alloc := pods.Requests[name], pods.Allocatable[name]
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f%%\n", name, req.String(), alloc.String(), utilization(pods.Requests, pods.Allocatable, name))
+		}
+
+		_, _ = fmt.Fprintln(tw, "\nPRIORITY CLASS\tPODS")
+		writeCounts(tw, report.PriorityClassCounts)
+
+		_, _ = fmt.Fprintln(tw, "\nTOP NAMESPACE\tPODS")
+		for _, nc := range report.TopNamespaces {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\n", nc.Namespace, nc.NumPods)
+		}
+		return tw.Flush()
+	})
+}
+
+func writeCounts(w io.Writer, counts map[string]int) {
+	for _, k := range slices.SortedFunc(maps.Keys(counts), strings.Compare) {
+		_, _ = fmt.Fprintf(w, "%s\t%d\n", k, counts[k])
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	"slices"
+	"testing"
+)
+
+func TestInspectObjDirTopNamespaces(t *testing.T) {
+	baseObjDir := t.TempDir()
+	for _, p := range []struct{ ns, name string }{
+		{"b", "p1"}, {"b", "p2"}, {"a", "p1"}, {"a", "p2"}, {"c", "p1"}, {"c", "p2"}, {"c", "p3"}, {"d", "p1"},
+	} {
+		pod := newPod(p.name, "n1", "100m")
+		pod.SetNamespace(p.ns)
+		writeObj(t, baseObjDir, podsGVR, pod)
+	}
+	writeObj(t, baseObjDir, nodesGVR, newNode("n1"))
+
+	report, err := InspectObjDir(baseObjDir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []api.NamespaceCount{{Namespace: "c", NumPods: 3}, {Namespace: "a", NumPods: 2}, {Namespace: "b", NumPods: 2}}
+	if !slices.Equal(report.TopNamespaces, expected) {
+		t.Errorf("expected top namespaces %v, got %v", expected, report.TopNamespaces)
+	}
+	if report.NumObjs != 9 || report.Pods.NumPods != 8 || report.Pods.NumBound != 8 {
+		t.Errorf("unexpected counts: objs=%d pods=%+v", report.NumObjs, report.Pods)
+	}
+
+	for topN, numExpected := range map[int]int{0: 0, -1: 4, 10: 4} {
+		report, err = InspectObjDir(baseObjDir, topN)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.TopNamespaces) != numExpected {
+			t.Errorf("expected %d top namespaces for top %d, got %v", numExpected, topN, report.TopNamespaces)
+		}
+	}
+}
@@ -22,7 +22,7 @@ func TestCreateKubeClientKine(t *testing.T) {
 		return
 	}
 	slog.Info("KUBECONFIG size", "size", fi.Size())
-	client, err := clientutil.CreateKubeClient(kubeconfig, 20)
+	client, err := clientutil.CreateKubeClient(kubeconfig, 30, 20)
 	if err != nil {
 		t.Error(err)
 		return
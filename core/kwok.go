@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"log/slog"
+	"os"
+)
+
+const kwokConfigPath = "templates/kwok-config.yaml"
+
+// MarkKWOKNode annotates the given node to be managed by a KWOK controller. Other objects are left untouched.
+func MarkKWOKNode(obj *unstructured.Unstructured) {
+	if obj.GetKind() != "Node" {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[api.AnnotationKWOKNode] = api.AnnotationKWOKNodeValue
+	obj.SetAnnotations(annotations)
+}
+
+// GenKWOKConfiguration writes a KWOK configuration to targetPath with Stages that keep the nodes annotated by
+// MarkKWOKNode Ready and advance the pods bound to them to Running. The configuration is written verbatim as its
+// status templates are rendered by the KWOK controller.
+func GenKWOKConfiguration(targetPath string) error {
+	data, err := templateFS.ReadFile(kwokConfigPath)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read %q: %w", api.ErrGenKWOKConfig, kwokConfigPath, err)
+	}
+	err = os.WriteFile(targetPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: cannot write %q: %w", api.ErrGenKWOKConfig, targetPath, err)
+	}
+	slog.Info("Wrote KWOK configuration.", "targetPath", targetPath)
+	return nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
+	"testing"
+)
+
+func TestGenKWOKConfiguration(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "kwok-config.yaml")
+	if err := GenKWOKConfiguration(targetPath); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, doc := range strings.Split(string(data), "\n---\n") {
+		var obj unstructured.Unstructured
+		if err = yaml.Unmarshal([]byte(doc), &obj.Object); err != nil {
+			t.Fatalf("invalid KWOK config document: %v\n%s", err, doc)
+		}
+		kinds = append(kinds, obj.GetKind())
+	}
+	if len(kinds) < 2 || kinds[0] != "KwokConfiguration" || kinds[1] != "Stage" {
+		t.Errorf("unexpected KWOK config kinds: %v", kinds)
+	}
+
+	node := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "Node", "metadata": map[string]any{"name": "n1"}}}
+	MarkKWOKNode(node)
+	if node.GetAnnotations()[api.AnnotationKWOKNode] != api.AnnotationKWOKNodeValue {
+		t.Errorf("expected node to be annotated for KWOK: %v", node.GetAnnotations())
+	}
+}
@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"log/slog"
+	"time"
+)
+
+const nodeLeaseDurationSeconds = 40
+
+var (
+	// lifecycleFieldManager is the field manager of the node and pod status applied by SimulateLifecycle. It differs
+	// from the field manager of uploads so that applying partial status does not drop the uploaded status fields.
+	lifecycleFieldManager = api.ProgramName + "-lifecycle"
+	leasesGVR             = coordinationv1.SchemeGroupVersion.WithResource("leases")
+)
+
+func (g *GardenerShootCopier) SimulateLifecycle(ctx context.Context, interval time.Duration) error {
+	ns := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]any{"name": corev1.NamespaceNodeLease},
+	}}
+	_, err := g.dynamicClient.Resource(corev1.SchemeGroupVersion.WithResource("namespaces")).Apply(ctx, ns.GetName(), ns, metav1.ApplyOptions{FieldManager: lifecycleFieldManager, Force: true})
+	if err != nil {
+		return fmt.Errorf("%w: cannot apply namespace %q: %w", api.ErrSimulateLifecycle, corev1.NamespaceNodeLease, err)
+	}
+	slog.Info("Simulating node and pod lifecycle until cancelled.", "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		g.refreshLifecycle(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped simulating node and pod lifecycle.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshLifecycle renews the lease and Ready condition of every node, marks pending pods bound to nodes Running and
+// deletes terminating pods, bound or not, like a kubelet and the pod garbage collector would. Failures are logged and
+// retried on the next refresh.
+func (g *GardenerShootCopier) refreshLifecycle(ctx context.Context, now time.Time) {
+	nodeList, numRetries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(nodesGVR), metav1.ListOptions{})
+	if err != nil {
+		slog.Warn("Cannot list nodes.", "error", err)
+		return
+	}
+	nodeIPs := make(map[string]string, len(nodeList.Items))
+	for i := range nodeList.Items {
+		var node corev1.Node
+		if err = fromUnstructured(&nodeList.Items[i], &node); err != nil {
+			slog.Warn("Cannot convert node.", "error", err)
+			continue
+		}
+		nodeIPs[node.Name] = nodeInternalIP(&node)
+		lease := nodeLease(&node, now)
+		_, err = g.dynamicClient.Resource(leasesGVR).Namespace(corev1.NamespaceNodeLease).Apply(ctx, node.Name, lease, metav1.ApplyOptions{FieldManager: lifecycleFieldManager, Force: true})
+		if err != nil {
+			slog.Warn("Cannot renew node lease.", "node", node.Name, "error", err)
+		}
+		_, err = g.dynamicClient.Resource(nodesGVR).ApplyStatus(ctx, node.Name, nodeReadyStatus(&node, now), metav1.ApplyOptions{FieldManager: lifecycleFieldManager, Force: true})
+		if err != nil {
+			slog.Warn("Cannot refresh node Ready condition.", "node", node.Name, "error", err)
+		}
+	}
+
+	podList, retries, err := g.listWithRetry(ctx, g.dynamicClient.Resource(podsGVR), metav1.ListOptions{})
+	numRetries += retries
+	if err != nil {
+		slog.Warn("Cannot list pods.", "error", err)
+		return
+	}
+	var numStarted, numDeleted int
+	for i := range podList.Items {
+		var pod corev1.Pod
+		if err = fromUnstructured(&podList.Items[i], &pod); err != nil {
+			slog.Warn("Cannot convert pod.", "error", err)
+			continue
+		}
+		podClient := g.dynamicClient.Resource(podsGVR).Namespace(pod.Namespace)
+		switch {
+		case pod.DeletionTimestamp != nil:
+			err = podClient.Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: new(int64)})
+			if err != nil {
+				slog.Warn("Cannot delete terminating pod.", "pod", cache.NewObjectName(pod.Namespace, pod.Name).String(), "error", err)
+				continue
+			}
+			numDeleted++
+		case pod.Spec.NodeName != "" && !isPodTerminated(&pod) && pod.Status.Phase != corev1.PodRunning:
+			_, err = podClient.ApplyStatus(ctx, pod.Name, podRunningStatus(&pod, nodeIPs[pod.Spec.NodeName], now), metav1.ApplyOptions{FieldManager: lifecycleFieldManager, Force: true})
+			if err != nil {
+				slog.Warn("Cannot mark pod Running.", "pod", cache.NewObjectName(pod.Namespace, pod.Name).String(), "error", err)
+				continue
+			}
+			numStarted++
+		}
+	}
+	slog.Info("Refreshed node and pod lifecycle.", "numNodes", len(nodeList.Items), "numPodsStarted", numStarted, "numPodsDeleted", numDeleted, "numRetries", numRetries)
+}
+
+func nodeLease(node *corev1.Node, now time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": coordinationv1.SchemeGroupVersion.String(),
+		"kind":       "Lease",
+		"metadata": map[string]any{
+			"name":      node.Name,
+			"namespace": corev1.NamespaceNodeLease,
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Node",
+				"name":       node.Name,
+				"uid":        string(node.UID),
+			}},
+		},
+		"spec": map[string]any{
+			"holderIdentity":       node.Name,
+			"leaseDurationSeconds": int64(nodeLeaseDurationSeconds),
+			"renewTime":            now.UTC().Format(metav1.RFC3339Micro),
+		},
+	}}
+}
+
+// nodeReadyStatus returns the status with a fresh Ready condition to apply to the given node. The transition time is
+// kept if the node is already Ready.
+func nodeReadyStatus(node *corev1.Node, now time.Time) *unstructured.Unstructured {
+	transition := now
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+			transition = c.LastTransitionTime.Time
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Node",
+		"metadata":   map[string]any{"name": node.Name},
+		"status": map[string]any{
+			"conditions": []any{map[string]any{
+				"type":               string(corev1.NodeReady),
+				"status":             string(corev1.ConditionTrue),
+				"reason":             "KubeletReady",
+				"message":            "kubelet is posting ready status (simulated by " + api.ProgramName + ")",
+				"lastHeartbeatTime":  now.UTC().Format(time.RFC3339),
+				"lastTransitionTime": transition.UTC().Format(time.RFC3339),
+			}},
+		},
+	}}
+}
+
+// podRunningStatus returns the status to apply to the given pod so that it is Running with all containers ready.
+func podRunningStatus(pod *corev1.Pod, hostIP string, now time.Time) *unstructured.Unstructured {
+	ts := now.UTC().Format(time.RFC3339)
+	var conditions []any
+	for _, t := range []corev1.PodConditionType{corev1.PodInitialized, corev1.ContainersReady, corev1.PodReady} {
+		conditions = append(conditions, map[string]any{"type": string(t), "status": string(corev1.ConditionTrue), "lastTransitionTime": ts})
+	}
+	var containerStatuses []any
+	for _, c := range pod.Spec.Containers {
+		containerStatuses = append(containerStatuses, map[string]any{
+			"name":         c.Name,
+			"image":        c.Image,
+			"imageID":      "",
+			"ready":        true,
+			"started":      true,
+			"restartCount": int64(0),
+			"state":        map[string]any{"running": map[string]any{"startedAt": ts}},
+		})
+	}
+	status := map[string]any{
+		"phase":             string(corev1.PodRunning),
+		"conditions":        conditions,
+		"containerStatuses": containerStatuses,
+		"startTime":         ts,
+	}
+	if hostIP != "" {
+		status["hostIP"] = hostIP
+		status["hostIPs"] = []any{map[string]any{"ip": hostIP}}
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": pod.Name, "namespace": pod.Namespace},
+		"status":     status,
+	}}
+}
+
+func nodeInternalIP(node *corev1.Node) string {
+	for _, a := range node.Status.Addresses {
+		if a.Type == corev1.NodeInternalIP {
+			return a.Address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"testing"
+	"time"
+)
+
+func TestLifecycleStatus(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	readySince := metav1.NewTime(now.Add(-time.Hour))
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastTransitionTime: readySince},
+		}},
+	}
+	conditions, _, _ := unstructured.NestedSlice(nodeReadyStatus(node, now).Object, "status", "conditions")
+	if len(conditions) != 1 {
+		t.Fatalf("expected 1 node condition, got %d", len(conditions))
+	}
+	ready := conditions[0].(map[string]any)
+	if ready["lastHeartbeatTime"] != "2024-01-02T03:04:05Z" || ready["lastTransitionTime"] != "2024-01-02T02:04:05Z" {
+		t.Errorf("expected fresh heartbeat and kept transition time: %v", ready)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "n1", Containers: []corev1.Container{{Name: "a", Image: "img:1"}, {Name: "b", Image: "img:2"}}},
+	}
+	status := podRunningStatus(pod, "10.0.0.1", now).Object["status"].(map[string]any)
+	if status["phase"] != string(corev1.PodRunning) || status["hostIP"] != "10.0.0.1" {
+		t.Errorf("unexpected pod status: %v", status)
+	}
+	if containerStatuses := status["containerStatuses"].([]any); len(containerStatuses) != 2 || containerStatuses[1].(map[string]any)["image"] != "img:2" {
+		t.Errorf("unexpected container statuses: %v", containerStatuses)
+	}
+}
+
+func TestRefreshLifecycleDeletesTerminatingPods(t *testing.T) {
+	ctx := context.Background()
+	unbound, terminated := newPod("unbound", "", ""), newPod("terminated", "n1", "")
+	terminated.Object["status"] = map[string]any{"phase": string(corev1.PodSucceeded)}
+	for _, pod := range []*unstructured.Unstructured{unbound, terminated} {
+		pod.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR:  "PodList",
+		nodesGVR: "NodeList",
+	}, unbound, terminated, newPod("pending", "", ""))
+	g := &GardenerShootCopier{dynamicClient: client}
+	g.refreshLifecycle(ctx, time.Now())
+
+	podList, err := client.Resource(podsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(podList.Items) != 1 || podList.Items[0].GetName() != "pending" {
+		t.Errorf("expected terminating pods to be deleted even if unbound or terminated, got %v", podList.Items)
+	}
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io/fs"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ManifestFilename  = "manifest.json"
+	ChangelogFilename = "changelog.jsonl"
+)
+
+// LoadManifest loads the download manifest of the given obj dir. A nil manifest is returned if the obj dir has none.
+func LoadManifest(baseObjDir string) (*api.DownloadManifest, error) {
+	path := filepath.Join(baseObjDir, ManifestFilename)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %q: %w", api.ErrLoadObj, path, err)
+	}
+	var manifest api.DownloadManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal manifest %q: %w", api.ErrLoadObj, path, err)
+	}
+	return &manifest, nil
+}
+
+func writeManifest(baseObjDir string, manifest *api.DownloadManifest) error {
+	path := filepath.Join(baseObjDir, ManifestFilename)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: cannot marshal manifest: %w", api.ErrSaveObj, err)
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: cannot write manifest %q: %w", api.ErrSaveObj, path, err)
+	}
+	slog.Info("Wrote download manifest.", "path", path, "numResources", len(manifest.Resources))
+	return nil
+}
+
+func appendChangelog(baseObjDir string, changelog *api.DownloadChangelog) error {
+	path := filepath.Join(baseObjDir, ChangelogFilename)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: cannot open changelog %q: %w", api.ErrSaveObj, path, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	err = json.NewEncoder(f).Encode(changelog)
+	if err != nil {
+		return fmt.Errorf("%w: cannot write changelog %q: %w", api.ErrSaveObj, path, err)
+	}
+	slog.Info("Appended download changelog.", "path", path, "numAdded", len(changelog.Added), "numModified", len(changelog.Modified), "numDeleted", len(changelog.Deleted))
+	return nil
+}
+
+// downloadState tracks the manifest of an ongoing download and, for incremental downloads, the changes relative to the
+// manifest of the previous download. It is safe for concurrent use.
+type downloadState struct {
+	mu          sync.Mutex
+	baseObjDir  string
+	incremental bool
+	prev        *api.DownloadManifest
+	curr        api.DownloadManifest
+	changelog   api.DownloadChangelog
+}
+
+func newDownloadState(baseObjDir string, incremental bool) (*downloadState, error) {
+	s := &downloadState{
+		baseObjDir:  baseObjDir,
+		incremental: incremental,
+		curr:        api.DownloadManifest{Time: time.Now().UTC(), Resources: make(map[string]api.ResourceManifest)},
+	}
+	s.changelog.Time = s.curr.Time
+	if !incremental {
+		return s, nil
+	}
+	prev, err := LoadManifest(baseObjDir)
+	if err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		slog.Warn("No previous manifest found in obj dir. Downloading all objects.", "baseObjDir", baseObjDir)
+	} else {
+		s.changelog.PreviousTime = prev.Time
+	}
+	s.prev = prev
+	return s, nil
+}
+
+func (s *downloadState) recordShoot(shoot, seed api.ShootCoords) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.curr.Shoot = &shoot
+	if seed.Name != "" {
+		s.curr.Seed = &seed
+	}
+}
+
+// recordObj records the resourceVersion of the object in the given file of the resource dir and returns whether
+// the file needs to be written.
+func (s *downloadState) recordObj(gvr schema.GroupVersionResource, filename string, resourceVersion string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resourceManifest(gvr).ObjResourceVersions[filename] = resourceVersion
+	if !s.incremental {
+		return true
+	}
+	relPath := filepath.Join(resourceDirName(gvr), filename)
+	prevResourceVersion, ok := s.prevResourceVersion(gvr, filename)
+	if !ok {
+		s.changelog.Added = append(s.changelog.Added, relPath)
+		return true
+	}
+	if prevResourceVersion != resourceVersion {
+		s.changelog.Modified = append(s.changelog.Modified, relPath)
+		return true
+	}
+	// rewrite objects whose file went missing since the previous download
+	_, err := os.Stat(filepath.Join(s.baseObjDir, relPath))
+	return err != nil
+}
+
+// finish removes the files of objects that no longer exist for incremental downloads and saves the manifest along
+// with the changelog.
+func (s *downloadState) finish(gvrList []schema.GroupVersionResource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prev != nil {
+		for key, rm := range s.prev.Resources {
+			if _, ok := s.curr.Resources[key]; !ok {
+				// keep manifest of resources that were not part of this download
+				s.curr.Resources[key] = rm
+			}
+		}
+	}
+	if s.incremental && s.prev != nil {
+		for _, gvr := range gvrList {
+			key := api.FormatGVR(gvr)
+			curr := s.curr.Resources[key]
+			for filename := range s.prev.Resources[key].ObjResourceVersions {
+				if _, ok := curr.ObjResourceVersions[filename]; ok {
+					continue
+				}
+				relPath := filepath.Join(resourceDirName(gvr), filename)
+				err := os.Remove(filepath.Join(s.baseObjDir, relPath))
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("%w: cannot remove deleted object file %q: %w", api.ErrDownloadFailed, relPath, err)
+				}
+				slog.Info("Removed deleted object.", "path", relPath)
+				s.changelog.Deleted = append(s.changelog.Deleted, relPath)
+			}
+		}
+	}
+	err := writeManifest(s.baseObjDir, &s.curr)
+	if err != nil {
+		return err
+	}
+	if !s.incremental {
+		return nil
+	}
+	slices.Sort(s.changelog.Added)
+	slices.Sort(s.changelog.Modified)
+	slices.Sort(s.changelog.Deleted)
+	return appendChangelog(s.baseObjDir, &s.changelog)
+}
+
+func (s *downloadState) addRetries(n int) {
+	if n == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.curr.NumRetries += n
+}
+
+// counts returns the number of downloaded objects and retried list requests.
+func (s *downloadState) counts() (numObjs, numRetries int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rm := range s.curr.Resources {
+		numObjs += len(rm.ObjResourceVersions)
+	}
+	return numObjs, s.curr.NumRetries
+}
+
+func (s *downloadState) resourceManifest(gvr schema.GroupVersionResource) api.ResourceManifest {
+	key := api.FormatGVR(gvr)
+	rm, ok := s.curr.Resources[key]
+	if !ok {
+		rm = api.ResourceManifest{
+			ObjResourceVersions: make(map[string]string),
+		}
+		s.curr.Resources[key] = rm
+	}
+	return rm
+}
+
+func (s *downloadState) prevResourceVersion(gvr schema.GroupVersionResource, filename string) (string, bool) {
+	if s.prev == nil {
+		return "", false
+	}
+	rv, ok := s.prev.Resources[api.FormatGVR(gvr)].ObjResourceVersions[filename]
+	return rv, ok
+}
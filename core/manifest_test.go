@@ -0,0 +1,66 @@
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIncrementalDownloadState(t *testing.T) {
+	baseObjDir := t.TempDir()
+	podsGVR := corev1.SchemeGroupVersion.WithResource("pods")
+	resourceDir := filepath.Join(baseObjDir, resourceDirName(podsGVR))
+	if err := os.MkdirAll(resourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gvrList := []schema.GroupVersionResource{podsGVR}
+
+	state, err := newDownloadState(baseObjDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, rv := range map[string]string{"default@a.yaml": "1", "default@b.yaml": "2", "default@c.yaml": "3"} {
+		if !state.recordObj(podsGVR, name, rv) {
+			t.Errorf("expected full download to write %q", name)
+		}
+		if err = os.WriteFile(filepath.Join(resourceDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = state.finish(gvrList); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = newDownloadState(baseObjDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.recordObj(podsGVR, "default@a.yaml", "1") {
+		t.Errorf("expected unchanged object to be skipped")
+	}
+	if !state.recordObj(podsGVR, "default@b.yaml", "5") {
+		t.Errorf("expected modified object to be written")
+	}
+	if !state.recordObj(podsGVR, "default@d.yaml", "6") {
+		t.Errorf("expected added object to be written")
+	}
+	if err = state.finish(gvrList); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(resourceDir, "default@c.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected deleted object file to be removed, got %v", err)
+	}
+	cl := state.changelog
+	if len(cl.Added) != 1 || len(cl.Modified) != 1 || len(cl.Deleted) != 1 {
+		t.Errorf("unexpected changelog: %+v", cl)
+	}
+	manifest, err := LoadManifest(baseObjDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.Resources["v1/pods"].ObjResourceVersions["default@b.yaml"]; got != "5" {
+		t.Errorf("expected resourceVersion 5 of modified object, got %q", got)
+	}
+}
@@ -0,0 +1,266 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io/fs"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NodeTemplatesDirName is the name of the subtree of the obj dir holding the synthetic template nodes of the worker
+// pools of the shoot. It has the same layout as the obj dir itself.
+var NodeTemplatesDirName = "node-templates"
+
+const (
+	labelWorkerPool       = "worker.gardener.cloud/pool"
+	labelWorkerGroup      = "worker.garden.sapcloud.io/group"
+	defaultArchitecture   = "amd64"
+	nodeTemplateKubeletOS = "linux"
+)
+
+// shootSpec holds the fields of the gardener shoot spec used to derive node templates.
+type shootSpec struct {
+	Spec struct {
+		Region   string `json:"region"`
+		Provider struct {
+			Workers []shootWorker `json:"workers"`
+		} `json:"provider"`
+	} `json:"spec"`
+}
+
+type shootWorker struct {
+	Name    string `json:"name"`
+	Machine struct {
+		Type         string  `json:"type"`
+		Architecture *string `json:"architecture,omitempty"`
+	} `json:"machine"`
+	Minimum     int32             `json:"minimum"`
+	Maximum     int32             `json:"maximum"`
+	Zones       []string          `json:"zones,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Taints      []corev1.Taint    `json:"taints,omitempty"`
+}
+
+// machineClassNodeTemplate holds the node template of a machine-controller-manager MachineClass.
+type machineClassNodeTemplate struct {
+	NodeTemplate *struct {
+		Capacity     corev1.ResourceList `json:"capacity"`
+		InstanceType string              `json:"instanceType"`
+		Zone         string              `json:"zone"`
+	} `json:"nodeTemplate,omitempty"`
+}
+
+func (g *GardenerShootCopier) GenNodeTemplates(ctx context.Context, baseObjDir string) error {
+	if g.gardenClient == nil {
+		return fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, api.ErrMissingKubeConfig)
+	}
+	coords := g.cfg.Shoot
+	shoot, err := g.gardenClient.Resource(shootsGVR).Namespace(ProjectNamespace(coords.Project)).Get(ctx, coords.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("%w: cannot get shoot %s: %w", api.ErrGenNodeTemplates, coords, err)
+	}
+	nodes, err := loadResourceDirObjs(filepath.Join(baseObjDir, resourceDirName(nodesGVR)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+	}
+	machineClasses, err := loadResourceDirObjs(filepath.Join(baseObjDir, ControlDirName, resourceDirName(machineClassesGVR)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+	}
+	templates, err := NodeTemplates(shoot, machineClasses, nodes)
+	if err != nil {
+		return err
+	}
+
+	resourceDir := filepath.Join(baseObjDir, NodeTemplatesDirName, resourceDirName(nodesGVR))
+	err = os.MkdirAll(resourceDir, 0755)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create directory %q: %w", api.ErrGenNodeTemplates, resourceDir, err)
+	}
+	for _, t := range templates {
+		filename := filepath.Join(resourceDir, objFileName("", t.GetName()))
+		err = writeObjToYAMLFile(filename, t)
+		if err != nil {
+			return fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+		}
+		slog.Info("Generated node template", "filename", filename)
+	}
+	return nil
+}
+
+// NodeTemplates derives a synthetic template node for each worker pool and zone of the given shoot. The capacity and
+// allocatable of a template are estimated from the existing nodes of the same machine type or, if there are none, from
+// the node template of a MachineClass of the same machine type. Pools whose resources cannot be estimated are skipped.
+func NodeTemplates(shoot *unstructured.Unstructured, machineClasses []*unstructured.Unstructured, nodes []*unstructured.Unstructured) (templates []*unstructured.Unstructured, err error) {
+	var spec shootSpec
+	if err = fromUnstructured(shoot, &spec); err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+		return
+	}
+	nodesByType := make(map[string][]corev1.Node)
+	for _, u := range nodes {
+		var node corev1.Node
+		if err = fromUnstructured(u, &node); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+			return
+		}
+		instanceType := nodeInstanceType(&node)
+		nodesByType[instanceType] = append(nodesByType[instanceType], node)
+	}
+	for _, ns := range nodesByType {
+		slices.SortFunc(ns, func(a, b corev1.Node) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+	}
+	var classes []machineClassNodeTemplate
+	for _, u := range machineClasses {
+		var mc machineClassNodeTemplate
+		if err = fromUnstructured(u, &mc); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrGenNodeTemplates, err)
+			return
+		}
+		if mc.NodeTemplate != nil {
+			classes = append(classes, mc)
+		}
+	}
+
+	for _, w := range spec.Spec.Provider.Workers {
+		zones := w.Zones
+		if len(zones) == 0 {
+			zones = []string{""}
+		}
+		for _, zone := range zones {
+			capacity, allocatable, ok := estimateNodeResources(w.Machine.Type, zone, nodesByType, classes)
+			if !ok {
+				slog.Warn("Skipping node template of worker pool without nodes or machine class of its machine type.", "pool", w.Name, "machineType", w.Machine.Type, "zone", zone)
+				continue
+			}
+			var t *unstructured.Unstructured
+			t, err = nodeTemplate(spec.Spec.Region, w, zone, capacity, allocatable)
+			if err != nil {
+				return
+			}
+			templates = append(templates, t)
+		}
+	}
+	return
+}
+
+// estimateNodeResources returns the capacity and allocatable of a node of the given machine type in the given zone.
+func estimateNodeResources(machineType, zone string, nodesByType map[string][]corev1.Node, classes []machineClassNodeTemplate) (capacity, allocatable corev1.ResourceList, ok bool) {
+	if ns := nodesByType[machineType]; len(ns) > 0 {
+		node := ns[0]
+		for _, n := range ns {
+			if nodeZone(&n) == zone {
+				node = n
+				break
+			}
+		}
+		return node.Status.Capacity.DeepCopy(), node.Status.Allocatable.DeepCopy(), true
+	}
+	var match *machineClassNodeTemplate
+	for i, mc := range classes {
+		if mc.NodeTemplate.InstanceType != machineType {
+			continue
+		}
+		if match == nil || mc.NodeTemplate.Zone == zone {
+			match = &classes[i]
+		}
+	}
+	if match == nil {
+		return
+	}
+	// without a running node, the kube and system reserved resources are unknown
+	return match.NodeTemplate.Capacity.DeepCopy(), match.NodeTemplate.Capacity.DeepCopy(), true
+}
+
+func nodeTemplate(region string, w shootWorker, zone string, capacity, allocatable corev1.ResourceList) (*unstructured.Unstructured, error) {
+	name := "template-" + w.Name
+	if zone != "" {
+		name += "-" + zone
+	}
+	arch := defaultArchitecture
+	if w.Machine.Architecture != nil && *w.Machine.Architecture != "" {
+		arch = *w.Machine.Architecture
+	}
+	labels := map[string]string{
+		labelWorkerPool:                   w.Name,
+		labelWorkerGroup:                  w.Name,
+		corev1.LabelHostname:              name,
+		corev1.LabelArchStable:            arch,
+		corev1.LabelOSStable:              nodeTemplateKubeletOS,
+		corev1.LabelInstanceTypeStable:    w.Machine.Type,
+		corev1.LabelInstanceType:          w.Machine.Type,
+		corev1.LabelTopologyRegion:        region,
+		corev1.LabelFailureDomainBetaZone: zone,
+		corev1.LabelTopologyZone:          zone,
+	}
+	if zone == "" {
+		delete(labels, corev1.LabelTopologyZone)
+		delete(labels, corev1.LabelFailureDomainBetaZone)
+	}
+	for k, v := range w.Labels {
+		labels[k] = v
+	}
+	annotations := map[string]string{
+		api.AnnotationNodeTemplatePool: w.Name,
+		api.AnnotationNodeTemplateMin:  strconv.Itoa(int(w.Minimum)),
+		api.AnnotationNodeTemplateMax:  strconv.Itoa(int(w.Maximum)),
+	}
+	for k, v := range w.Annotations {
+		annotations[k] = v
+	}
+	node := corev1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		Spec:       corev1.NodeSpec{Taints: w.Taints},
+		Status: corev1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: allocatable,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Phase:       corev1.NodeRunning,
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&node)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot convert node template %q: %w", api.ErrGenNodeTemplates, name, err)
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	return u, nil
+}
+
+// loadResourceDirObjs loads the objects in the given resource dir. No objects are returned if the dir does not exist.
+func loadResourceDirObjs(resourceDir string) (objs []*unstructured.Unstructured, err error) {
+	entries, err := os.ReadDir(resourceDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read %q: %w", api.ErrCantReadObjDir, resourceDir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		obj, err := LoadObj(filepath.Join(resourceDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestNodeTemplates(t *testing.T) {
+	shoot := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "core.gardener.cloud/v1beta1",
+		"kind":       "Shoot",
+		"metadata":   map[string]any{"name": "aw", "namespace": "garden-dev"},
+		"spec": map[string]any{
+			"region": "eu-west-1",
+			"provider": map[string]any{"workers": []any{
+				map[string]any{
+					"name":    "a",
+					"machine": map[string]any{"type": "m5.large"},
+					"minimum": int64(1),
+					"maximum": int64(3),
+					"zones":   []any{"eu-west-1a", "eu-west-1b"},
+					"labels":  map[string]any{"team": "x"},
+					"taints":  []any{map[string]any{"key": "dedicated", "value": "x", "effect": "NoSchedule"}},
+				},
+				map[string]any{
+					"name":    "b",
+					"machine": map[string]any{"type": "m5.xlarge", "architecture": "arm64"},
+					"maximum": int64(2),
+					"zones":   []any{"eu-west-1a"},
+				},
+				map[string]any{
+					"name":    "c",
+					"machine": map[string]any{"type": "unknown"},
+					"zones":   []any{"eu-west-1a"},
+				},
+			}},
+		},
+	}}
+	node := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Node",
+		"metadata": map[string]any{"name": "n1", "labels": map[string]any{
+			corev1.LabelInstanceTypeStable: "m5.large",
+			corev1.LabelTopologyZone:       "eu-west-1a",
+		}},
+		"status": map[string]any{
+			"capacity":    map[string]any{"cpu": "2", "memory": "8Gi"},
+			"allocatable": map[string]any{"cpu": "1920m", "memory": "7Gi"},
+		},
+	}}
+	machineClass := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "machine.sapcloud.io/v1alpha1",
+		"kind":       "MachineClass",
+		"metadata":   map[string]any{"name": "shoot--dev--aw-b-z1", "namespace": "shoot--dev--aw"},
+		"nodeTemplate": map[string]any{
+			"instanceType": "m5.xlarge",
+			"zone":         "eu-west-1a",
+			"capacity":     map[string]any{"cpu": "4", "memory": "16Gi"},
+		},
+	}}
+
+	templates, err := NodeTemplates(shoot, []*unstructured.Unstructured{machineClass}, []*unstructured.Unstructured{node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]corev1.Node)
+	for _, u := range templates {
+		var n corev1.Node
+		if err = fromUnstructured(u, &n); err != nil {
+			t.Fatal(err)
+		}
+		byName[n.Name] = n
+	}
+	if len(byName) != 3 {
+		t.Fatalf("expected 3 node templates, got %v", templates)
+	}
+
+	a := byName["template-a-eu-west-1b"]
+	if a.Labels[corev1.LabelTopologyZone] != "eu-west-1b" || a.Labels["team"] != "x" || a.Labels[labelWorkerPool] != "a" {
+		t.Errorf("unexpected labels of template a: %v", a.Labels)
+	}
+	if len(a.Spec.Taints) != 1 || a.Spec.Taints[0].Key != "dedicated" {
+		t.Errorf("unexpected taints of template a: %v", a.Spec.Taints)
+	}
+	if a.Annotations[api.AnnotationNodeTemplateMin] != "1" || a.Annotations[api.AnnotationNodeTemplateMax] != "3" {
+		t.Errorf("unexpected annotations of template a: %v", a.Annotations)
+	}
+	if cpu := a.Status.Allocatable.Cpu(); cpu.MilliValue() != 1920 {
+		t.Errorf("expected allocatable cpu of template a to be estimated from node, got %s", cpu)
+	}
+
+	b := byName["template-b-eu-west-1a"]
+	if b.Labels[corev1.LabelArchStable] != "arm64" {
+		t.Errorf("unexpected architecture of template b: %v", b.Labels)
+	}
+	if cpu := b.Status.Capacity.Cpu(); cpu.Value() != 4 {
+		t.Errorf("expected capacity cpu of template b to be estimated from machine class, got %s", cpu)
+	}
+}
@@ -0,0 +1,239 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+)
+
+// PlacementReportFromCluster compares the placement of the pods in the given obj dir against their placement in the
+// cluster of the given client after being uploaded and re-scheduled.
+func PlacementReportFromCluster(ctx context.Context, client dynamic.Interface, baseObjDir string) (report api.PlacementReport, err error) {
+	nodes, err := loadResourceDirObjs(filepath.Join(baseObjDir, resourceDirName(nodesGVR)))
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+		return
+	}
+	original, err := loadResourceDirObjs(filepath.Join(baseObjDir, resourceDirName(podsGVR)))
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+		return
+	}
+	currentNodes, err := listObjs(ctx, client, nodesGVR)
+	if err != nil {
+		return
+	}
+	current, err := listObjs(ctx, client, podsGVR)
+	if err != nil {
+		return
+	}
+	// nodes of the target cluster which are not in the obj dir, ex: template nodes, are also candidates for placement
+	known := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		known[n.GetName()] = struct{}{}
+	}
+	for _, n := range currentNodes {
+		if _, ok := known[n.GetName()]; !ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return ComparePlacement(nodes, original, current)
+}
+
+func listObjs(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource) (objs []*unstructured.Unstructured, err error) {
+	objList, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		slog.Warn("Resource not found in cluster.", "gvr", gvr)
+		err = nil
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: failed to list objects for gvr %q: %w", api.ErrReportFailed, gvr, err)
+		return
+	}
+	for i := range objList.Items {
+		objs = append(objs, &objList.Items[i])
+	}
+	return
+}
+
+// ComparePlacement compares the original placement of pods on the given nodes against their current placement. The
+// original node of a pod is taken from the original pods, falling back to the api.AnnotationOriginalNodeName
+// annotation of the current pod. Current pods without an original placement and terminated pods are ignored.
+func ComparePlacement(nodes, original, current []*unstructured.Unstructured) (report api.PlacementReport, err error) {
+	type podPlacement struct {
+		before, after string
+		daemon        bool
+		requests      corev1.ResourceList
+	}
+	placements := make(map[string]*podPlacement)
+	for _, o := range original {
+		var pod corev1.Pod
+		if err = fromUnstructured(o, &pod); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+			return
+		}
+		if isPodTerminated(&pod) {
+			continue
+		}
+		key := cache.NewObjectName(pod.Namespace, pod.Name).String()
+		placements[key] = &podPlacement{before: pod.Spec.NodeName, daemon: isDaemonSetPod(&pod), requests: podRequests(&pod)}
+	}
+	for _, o := range current {
+		var pod corev1.Pod
+		if err = fromUnstructured(o, &pod); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+			return
+		}
+		if isPodTerminated(&pod) {
+			continue
+		}
+		key := cache.NewObjectName(pod.Namespace, pod.Name).String()
+		p, ok := placements[key]
+		if !ok {
+			originalNode, ok := pod.Annotations[api.AnnotationOriginalNodeName]
+			if !ok {
+				continue
+			}
+			p = &podPlacement{before: originalNode, daemon: isDaemonSetPod(&pod), requests: podRequests(&pod)}
+			placements[key] = p
+		}
+		p.after = pod.Spec.NodeName
+	}
+
+	nodePlacements := make(map[string]*api.NodePlacement, len(nodes))
+	for _, o := range nodes {
+		var node corev1.Node
+		if err = fromUnstructured(o, &node); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+			return
+		}
+		nodePlacements[node.Name] = &api.NodePlacement{Name: node.Name, Allocatable: node.Status.Allocatable}
+	}
+	nodePlacement := func(name string) *api.NodePlacement {
+		np, ok := nodePlacements[name]
+		if !ok {
+			np = &api.NodePlacement{Name: name}
+			nodePlacements[name] = np
+		}
+		return np
+	}
+	usedBefore, usedAfter := make(map[string]struct{}), make(map[string]struct{})
+	addUsage := func(usage *api.NodeUsage, used map[string]struct{}, nodeName string, p *podPlacement) {
+		usage.NumPods++
+		if usage.Requests == nil {
+			usage.Requests = corev1.ResourceList{}
+		}
+		addResources(usage.Requests, p.requests)
+		if !p.daemon {
+			used[nodeName] = struct{}{}
+		}
+	}
+
+	report.NumPods = len(placements)
+	for _, key := range slices.Sorted(maps.Keys(placements)) {
+		p := placements[key]
+		if p.before != "" {
+			addUsage(&nodePlacement(p.before).Before, usedBefore, p.before, p)
+		}
+		if p.after != "" {
+			addUsage(&nodePlacement(p.after).After, usedAfter, p.after, p)
+		}
+		switch {
+		case p.before == p.after:
+			continue
+		case p.after == "":
+			report.NumUnplaced++
+		case p.before == "":
+			report.NumNewlyPlaced++
+		default:
+			report.NumMoved++
+		}
+		report.Moved = append(report.Moved, api.PodMove{Pod: key, From: p.before, To: p.after})
+	}
+
+	for _, np := range nodePlacements {
+		for _, usage := range []*api.NodeUsage{&np.Before, &np.After} {
+			usage.CPUUtilization = utilization(usage.Requests, np.Allocatable, corev1.ResourceCPU)
+			usage.MemoryUtilization = utilization(usage.Requests, np.Allocatable, corev1.ResourceMemory)
+		}
+		report.Nodes = append(report.Nodes, *np)
+	}
+	slices.SortFunc(report.Nodes, func(a, b api.NodePlacement) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	report.Before = placementSummary(report.Nodes, usedBefore, func(np *api.NodePlacement) *api.NodeUsage { return &np.Before })
+	report.After = placementSummary(report.Nodes, usedAfter, func(np *api.NodePlacement) *api.NodeUsage { return &np.After })
+	return
+}
+
+// placementSummary computes the bin-packing efficiency over the given used nodes. The requests of DaemonSet pods on
+// nodes which are otherwise empty do not count towards the efficiency.
+func placementSummary(nodes []api.NodePlacement, used map[string]struct{}, usageFn func(np *api.NodePlacement) *api.NodeUsage) (summary api.PlacementSummary) {
+	requests, allocatable := corev1.ResourceList{}, corev1.ResourceList{}
+	for i := range nodes {
+		np := &nodes[i]
+		if _, ok := used[np.Name]; !ok {
+			summary.NumNodesEmpty++
+			continue
+		}
+		summary.NumNodesUsed++
+		addResources(requests, usageFn(np).Requests)
+		addResources(allocatable, np.Allocatable)
+	}
+	summary.CPUEfficiency = utilization(requests, allocatable, corev1.ResourceCPU)
+	summary.MemoryEfficiency = utilization(requests, allocatable, corev1.ResourceMemory)
+	return
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	ref := metav1.GetControllerOf(pod)
+	return ref != nil && ref.Kind == "DaemonSet"
+}
+
+// WritePlacementReport writes the given placement report to w in the given format.
+func WritePlacementReport(w io.Writer, report api.PlacementReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NODE\tPODS BEFORE\tCPU BEFORE\tMEMORY BEFORE\tPODS AFTER\tCPU AFTER\tMEMORY AFTER")
+		for _, np := range report.Nodes {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%.1f%%\t%d\t%.1f%%\t%.1f%%\n", np.Name,
+				np.Before.NumPods, np.Before.CPUUtilization, np.Before.MemoryUtilization,
+				np.After.NumPods, np.After.CPUUtilization, np.After.MemoryUtilization)
+		}
+
+		_, _ = fmt.Fprintln(tw, "\nPLACEMENT\tNODES USED\tNODES EMPTY\tCPU EFFICIENCY\tMEMORY EFFICIENCY")
+		for _, s := range []struct {
+			name    string
+			summary api.PlacementSummary
+		}{{"before", report.Before}, {"after", report.After}} {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\t%.1f%%\n", s.name, s.summary.NumNodesUsed, s.summary.NumNodesEmpty,
+				s.summary.CPUEfficiency, s.summary.MemoryEfficiency)
+		}
+
+		_, _ = fmt.Fprintln(tw, "\nPODS\tMOVED\tUNPLACED\tNEWLY PLACED")
+		_, _ = fmt.Fprintf(tw, "%d\t%d\t%d\t%d\n", report.NumPods, report.NumMoved, report.NumUnplaced, report.NumNewlyPlaced)
+
+		if len(report.Moved) > 0 {
+			_, _ = fmt.Fprintln(tw, "\nPOD\tFROM\tTO")
+			for _, m := range report.Moved {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Pod, cmp.Or(m.From, "<none>"), cmp.Or(m.To, "<none>"))
+			}
+		}
+		return tw.Flush()
+	})
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+// newDaemonPod returns a pod like newPod that is controlled by a DaemonSet.
+func newDaemonPod(name, nodeName, cpu string) *unstructured.Unstructured {
+	controller := true
+	pod := newPod(name, nodeName, cpu)
+	pod.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", UID: "1", Controller: &controller}})
+	return pod
+}
+
+func TestComparePlacement(t *testing.T) {
+	nodes := []*unstructured.Unstructured{newNode("n1"), newNode("n2")}
+	original := []*unstructured.Unstructured{
+		newPod("a", "n1", "500m"),
+		newPod("b", "n2", "500m"),
+		newDaemonPod("ds1", "n1", "100m"),
+		newDaemonPod("ds2", "n2", "100m"),
+		newPod("c", "", "500m"),
+	}
+	current := []*unstructured.Unstructured{
+		newPod("a", "n1", "500m"),
+		newPod("b", "n1", "500m"),
+		newDaemonPod("ds1", "n1", "100m"),
+		newDaemonPod("ds2", "n2", "100m"),
+		newPod("c", "n1", "500m"),
+	}
+
+	report, err := ComparePlacement(nodes, original, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumPods != 5 || report.NumMoved != 1 || report.NumNewlyPlaced != 1 || report.NumUnplaced != 0 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if report.Before.NumNodesUsed != 2 || report.Before.NumNodesEmpty != 0 {
+		t.Errorf("unexpected placement before: %+v", report.Before)
+	}
+	if report.After.NumNodesUsed != 1 || report.After.NumNodesEmpty != 1 {
+		t.Errorf("unexpected placement after: %+v", report.After)
+	}
+	if report.After.CPUEfficiency != 80 {
+		t.Errorf("expected cpu efficiency of 80%% after placement, got %v", report.After.CPUEfficiency)
+	}
+	if len(report.Nodes) != 2 || report.Nodes[0].After.NumPods != 4 || report.Nodes[0].After.CPUUtilization != 80 {
+		t.Errorf("unexpected node placements: %+v", report.Nodes)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	unknownLabelValue = "<unknown>"
+)
+
+// podRequests returns the effective resource requests of the given pod which is the larger of the sum of the requests
+// of all containers and the largest request of any init container, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	reqs := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResources(reqs, c.Resources.Requests)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if curr, ok := reqs[name]; !ok || q.Cmp(curr) > 0 {
+				reqs[name] = q.DeepCopy()
+			}
+		}
+	}
+	addResources(reqs, pod.Spec.Overhead)
+	return reqs
+}
+
+// addResources adds the quantities of src to the quantities of the same resource in dst.
+func addResources(dst corev1.ResourceList, src corev1.ResourceList) {
+	for name, q := range src {
+		curr, ok := dst[name]
+		if !ok {
+			dst[name] = q.DeepCopy()
+			continue
+		}
+		curr.Add(q)
+		dst[name] = curr
+	}
+}
+
+// utilization returns the percentage of the allocatable quantity of the given resource that is requested.
+func utilization(requests, allocatable corev1.ResourceList, name corev1.ResourceName) float64 {
+	alloc, ok := allocatable[name]
+	if !ok || alloc.IsZero() {
+		return 0
+	}
+	req := requests[name]
+	return float64(req.MilliValue()) * 100 / float64(alloc.MilliValue())
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func nodeInstanceType(node *corev1.Node) string {
+	return labelValue(node.Labels, corev1.LabelInstanceTypeStable, corev1.LabelInstanceType)
+}
+
+func nodeZone(node *corev1.Node) string {
+	return labelValue(node.Labels, corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone)
+}
+
+// labelValue returns the value of the first of the given label keys present in labels.
+func labelValue(labels map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := labels[k]; ok {
+			return v
+		}
+	}
+	return unknownLabelValue
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	namespaceGVK      = corev1.SchemeGroupVersion.WithKind("Namespace")
+	serviceAccountGVK = corev1.SchemeGroupVersion.WithKind("ServiceAccount")
+	configMapGVK      = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	secretGVK         = corev1.SchemeGroupVersion.WithKind("Secret")
+	pvcGVK            = corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+	pvGVK             = corev1.SchemeGroupVersion.WithKind("PersistentVolume")
+	nodeGVK           = corev1.SchemeGroupVersion.WithKind("Node")
+	storageClassGVK   = storagev1.SchemeGroupVersion.WithKind("StorageClass")
+	priorityClassGVK  = schema.GroupVersionKind{Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"}
+
+	nodesGVR    = corev1.SchemeGroupVersion.WithResource("nodes")
+	csiNodesGVR = storagev1.SchemeGroupVersion.WithResource("csinodes")
+)
+
+// objRef is a reference from one object to another object that it depends upon.
+type objRef struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+	// Field is the path of the field in the referring object holding the reference.
+	Field string
+	// Optional is true if the referring object can exist without the referenced object.
+	Optional bool
+}
+
+func (r objRef) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s", r.GVK.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", r.GVK.Kind, r.Namespace, r.Name)
+}
+
+// objRefs returns the references from the given object to the objects that it depends upon.
+func objRefs(obj *unstructured.Unstructured) (refs []objRef, err error) {
+	ns := obj.GetNamespace()
+	if ns != "" {
+		refs = append(refs, objRef{GVK: namespaceGVK, Name: ns, Field: "metadata.namespace"})
+	}
+	for _, o := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(o.APIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid ownerReference apiVersion %q in %s %q: %w", api.ErrLoadObj, o.APIVersion, obj.GetKind(), obj.GetName(), err)
+		}
+		refs = append(refs, objRef{GVK: gv.WithKind(o.Kind), Namespace: ns, Name: o.Name, Field: "metadata.ownerReferences", Optional: true})
+	}
+	switch obj.GetKind() {
+	case "Pod":
+		var pod corev1.Pod
+		if err = fromUnstructured(obj, &pod); err != nil {
+			return
+		}
+		refs = append(refs, podRefs(&pod)...)
+	case "PersistentVolumeClaim":
+		var pvc corev1.PersistentVolumeClaim
+		if err = fromUnstructured(obj, &pvc); err != nil {
+			return
+		}
+		if pvc.Spec.VolumeName != "" {
+			refs = append(refs, objRef{GVK: pvGVK, Name: pvc.Spec.VolumeName, Field: "spec.volumeName"})
+		}
+		if sc := pvc.Spec.StorageClassName; sc != nil && *sc != "" {
+			refs = append(refs, objRef{GVK: storageClassGVK, Name: *sc, Field: "spec.storageClassName"})
+		}
+	case "PersistentVolume":
+		var pv corev1.PersistentVolume
+		if err = fromUnstructured(obj, &pv); err != nil {
+			return
+		}
+		if pv.Spec.StorageClassName != "" {
+			refs = append(refs, objRef{GVK: storageClassGVK, Name: pv.Spec.StorageClassName, Field: "spec.storageClassName"})
+		}
+	case "ServiceAccount":
+		var sa corev1.ServiceAccount
+		if err = fromUnstructured(obj, &sa); err != nil {
+			return
+		}
+		for _, s := range sa.ImagePullSecrets {
+			refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: s.Name, Field: "imagePullSecrets", Optional: true})
+		}
+	case "VolumeAttachment":
+		var va storagev1.VolumeAttachment
+		if err = fromUnstructured(obj, &va); err != nil {
+			return
+		}
+		refs = append(refs, objRef{GVK: nodeGVK, Name: va.Spec.NodeName, Field: "spec.nodeName"})
+		if pvName := va.Spec.Source.PersistentVolumeName; pvName != nil && *pvName != "" {
+			refs = append(refs, objRef{GVK: pvGVK, Name: *pvName, Field: "spec.source.persistentVolumeName"})
+		}
+	case "CSINode":
+		refs = append(refs, objRef{GVK: nodeGVK, Name: obj.GetName(), Field: "metadata.name"})
+	}
+	return
+}
+
+func podRefs(pod *corev1.Pod) (refs []objRef) {
+	ns := pod.Namespace
+	saName := pod.Spec.ServiceAccountName
+	if saName == "" {
+		saName = "default"
+	}
+	refs = append(refs, objRef{GVK: serviceAccountGVK, Namespace: ns, Name: saName, Field: "spec.serviceAccountName"})
+	if pod.Spec.PriorityClassName != "" {
+		refs = append(refs, objRef{GVK: priorityClassGVK, Name: pod.Spec.PriorityClassName, Field: "spec.priorityClassName"})
+	}
+	for _, s := range pod.Spec.ImagePullSecrets {
+		refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: s.Name, Field: "spec.imagePullSecrets", Optional: true})
+	}
+	for _, v := range pod.Spec.Volumes {
+		field := "spec.volumes[" + v.Name + "]"
+		switch {
+		case v.ConfigMap != nil:
+			refs = append(refs, objRef{GVK: configMapGVK, Namespace: ns, Name: v.ConfigMap.Name, Field: field, Optional: isOptional(v.ConfigMap.Optional)})
+		case v.Secret != nil:
+			refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: v.Secret.SecretName, Field: field, Optional: isOptional(v.Secret.Optional)})
+		case v.PersistentVolumeClaim != nil:
+			refs = append(refs, objRef{GVK: pvcGVK, Namespace: ns, Name: v.PersistentVolumeClaim.ClaimName, Field: field})
+		case v.Projected != nil:
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					refs = append(refs, objRef{GVK: configMapGVK, Namespace: ns, Name: src.ConfigMap.Name, Field: field, Optional: isOptional(src.ConfigMap.Optional)})
+				}
+				if src.Secret != nil {
+					refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: src.Secret.Name, Field: field, Optional: isOptional(src.Secret.Optional)})
+				}
+			}
+		}
+	}
+	var containers []corev1.Container
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+	for _, c := range containers {
+		field := "spec.containers[" + c.Name + "]"
+		for _, e := range c.EnvFrom {
+			if e.ConfigMapRef != nil {
+				refs = append(refs, objRef{GVK: configMapGVK, Namespace: ns, Name: e.ConfigMapRef.Name, Field: field + ".envFrom", Optional: isOptional(e.ConfigMapRef.Optional)})
+			}
+			if e.SecretRef != nil {
+				refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: e.SecretRef.Name, Field: field + ".envFrom", Optional: isOptional(e.SecretRef.Optional)})
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if r := e.ValueFrom.ConfigMapKeyRef; r != nil {
+				refs = append(refs, objRef{GVK: configMapGVK, Namespace: ns, Name: r.Name, Field: field + ".env[" + e.Name + "]", Optional: isOptional(r.Optional)})
+			}
+			if r := e.ValueFrom.SecretKeyRef; r != nil {
+				refs = append(refs, objRef{GVK: secretGVK, Namespace: ns, Name: r.Name, Field: field + ".env[" + e.Name + "]", Optional: isOptional(r.Optional)})
+			}
+		}
+	}
+	return
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+func fromUnstructured(obj *unstructured.Unstructured, target any) error {
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, target)
+	if err != nil {
+		return fmt.Errorf("%w: cannot convert %s %q in namespace %q: %w", api.ErrLoadObj, obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"testing"
+)
+
+func TestObjRefs(t *testing.T) {
+	owned := newObj("ConfigMap", "default", "cm")
+	owned.Object["metadata"].(map[string]any)["ownerReferences"] = []any{
+		map[string]any{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "rs", "uid": "1"},
+	}
+
+	pod := newPod("p", "n1", "")
+	spec := pod.Object["spec"].(map[string]any)
+	spec["serviceAccountName"] = "sa"
+	spec["priorityClassName"] = "high"
+	spec["volumes"] = []any{
+		map[string]any{"name": "v1", "configMap": map[string]any{"name": "cm1", "optional": true}},
+		map[string]any{"name": "v2", "secret": map[string]any{"secretName": "s1"}},
+		map[string]any{"name": "v3", "persistentVolumeClaim": map[string]any{"claimName": "pvc1"}},
+		map[string]any{"name": "v4", "projected": map[string]any{"sources": []any{
+			map[string]any{"configMap": map[string]any{"name": "cm2"}},
+			map[string]any{"secret": map[string]any{"name": "s2"}},
+		}}},
+	}
+	spec["containers"] = []any{map[string]any{
+		"name":    "c",
+		"envFrom": []any{map[string]any{"secretRef": map[string]any{"name": "s3"}}},
+		"env": []any{
+			map[string]any{"name": "A", "value": "a"},
+			map[string]any{"name": "B", "valueFrom": map[string]any{"configMapKeyRef": map[string]any{"name": "cm3", "key": "k"}}},
+		},
+	}}
+
+	defaultSAPod := newPod("q", "", "")
+	pvc := newObj("PersistentVolumeClaim", "default", "pvc1")
+	pvc.Object["spec"] = map[string]any{"volumeName": "pv1", "storageClassName": "standard"}
+	sa := newObj("ServiceAccount", "default", "sa")
+	sa.Object["imagePullSecrets"] = []any{map[string]any{"name": "registry"}}
+	va := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.k8s.io/v1",
+		"kind":       "VolumeAttachment",
+		"metadata":   map[string]any{"name": "va"},
+		"spec":       map[string]any{"attacher": "csi", "nodeName": "n1", "source": map[string]any{"persistentVolumeName": "pv1"}},
+	}}
+	csiNode := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "storage.k8s.io/v1",
+		"kind":       "CSINode",
+		"metadata":   map[string]any{"name": "n1"},
+	}}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want []objRef
+	}{
+		{"cluster scoped without refs", newNode("n1"), nil},
+		{"owner references", owned, []objRef{
+			{GVK: namespaceGVK, Name: "default", Field: "metadata.namespace"},
+			{GVK: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, Namespace: "default", Name: "rs", Field: "metadata.ownerReferences", Optional: true},
+		}},
+		{"pod", pod, []objRef{
+			{GVK: namespaceGVK, Name: "default", Field: "metadata.namespace"},
+			{GVK: serviceAccountGVK, Namespace: "default", Name: "sa", Field: "spec.serviceAccountName"},
+			{GVK: priorityClassGVK, Name: "high", Field: "spec.priorityClassName"},
+			{GVK: configMapGVK, Namespace: "default", Name: "cm1", Field: "spec.volumes[v1]", Optional: true},
+			{GVK: secretGVK, Namespace: "default", Name: "s1", Field: "spec.volumes[v2]"},
+			{GVK: pvcGVK, Namespace: "default", Name: "pvc1", Field: "spec.volumes[v3]"},
+			{GVK: configMapGVK, Namespace: "default", Name: "cm2", Field: "spec.volumes[v4]"},
+			{GVK: secretGVK, Namespace: "default", Name: "s2", Field: "spec.volumes[v4]"},
+			{GVK: secretGVK, Namespace: "default", Name: "s3", Field: "spec.containers[c].envFrom"},
+			{GVK: configMapGVK, Namespace: "default", Name: "cm3", Field: "spec.containers[c].env[B]"},
+		}},
+		{"pod with default service account", defaultSAPod, []objRef{
+			{GVK: namespaceGVK, Name: "default", Field: "metadata.namespace"},
+			{GVK: serviceAccountGVK, Namespace: "default", Name: "default", Field: "spec.serviceAccountName"},
+		}},
+		{"persistent volume claim", pvc, []objRef{
+			{GVK: namespaceGVK, Name: "default", Field: "metadata.namespace"},
+			{GVK: pvGVK, Name: "pv1", Field: "spec.volumeName"},
+			{GVK: storageClassGVK, Name: "standard", Field: "spec.storageClassName"},
+		}},
+		{"service account", sa, []objRef{
+			{GVK: namespaceGVK, Name: "default", Field: "metadata.namespace"},
+			{GVK: secretGVK, Namespace: "default", Name: "registry", Field: "imagePullSecrets", Optional: true},
+		}},
+		{"volume attachment", va, []objRef{
+			{GVK: nodeGVK, Name: "n1", Field: "spec.nodeName"},
+			{GVK: pvGVK, Name: "pv1", Field: "spec.source.persistentVolumeName"},
+		}},
+		{"csi node", csiNode, []objRef{
+			{GVK: nodeGVK, Name: "n1", Field: "metadata.name"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := objRefs(tt.obj)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("objRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	invalid := newObj("ConfigMap", "default", "cm")
+	invalid.Object["metadata"].(map[string]any)["ownerReferences"] = []any{
+		map[string]any{"apiVersion": "a/b/c", "kind": "ReplicaSet", "name": "rs", "uid": "1"},
+	}
+	if _, err := objRefs(invalid); err == nil {
+		t.Errorf("expected error for invalid ownerReference apiVersion")
+	}
+}
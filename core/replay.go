@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/restmapper"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// replaySleep waits for the given delay between replayed events. It is replaced in tests to replay without waiting.
+var replaySleep = sleepContext
+
+func (g *GardenerShootCopier) ReplayEvents(ctx context.Context, baseObjDir string, opts api.ReplayOpts) error {
+	eventLogPath := filepath.Join(baseObjDir, EventLogFilename)
+	events, err := LoadObjEvents(eventLogPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrReplayFailed, err)
+	}
+	if len(events) == 0 {
+		slog.Warn("No events to replay.", "eventLogPath", eventLogPath)
+		return nil
+	}
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+	apiGroupResources, err := restmapper.GetAPIGroupResources(g.discoveryClient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch API group resources: %w", api.ErrDiscovery, err)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(apiGroupResources)
+	kindUploaders := make(map[string]*KindUploader)
+	uploadCounter := &atomic.Uint32{}
+
+	begin := time.Now()
+	first := events[0].Time
+	startTime := first.Add(opts.Start)
+	var stopTime time.Time
+	if opts.Stop > 0 {
+		stopTime = first.Add(opts.Stop)
+	}
+	slog.Info("Replaying events.", "eventLogPath", eventLogPath, "numEvents", len(events), "speed", opts.Speed, "start", opts.Start, "stop", opts.Stop)
+	var prev time.Time
+	numReplayed := 0
+	for _, e := range events {
+		if e.Time.Before(startTime) {
+			continue
+		}
+		if !stopTime.IsZero() && e.Time.After(stopTime) {
+			break
+		}
+		if !prev.IsZero() {
+			delay := time.Duration(float64(e.Time.Sub(prev)) / opts.Speed)
+			err = replaySleep(ctx, delay)
+			if err != nil {
+				slog.Info("Replay cancelled.", "numReplayed", numReplayed)
+				return nil
+			}
+		}
+		prev = e.Time
+		u, ok := kindUploaders[e.Object.GetKind()]
+		if !ok {
+			u, err = newKindUploader(mapper, g.dynamicClient, e.Object.GroupVersionKind(), uploadCounter, g.cfg)
+			if err != nil {
+				return fmt.Errorf("%w: %w", api.ErrReplayFailed, err)
+			}
+			kindUploaders[e.Object.GetKind()] = u
+		}
+		err = g.replayEvent(ctx, u, e)
+		if err != nil {
+			return fmt.Errorf("%w: failed to replay %s event at %s: %w", api.ErrReplayFailed, e.Type, e.Time, err)
+		}
+		numReplayed++
+		slog.Info("Replayed event.", "type", e.Type, "kind", e.Object.GetKind(), "name", e.Object.GetName(), "namespace", e.Object.GetNamespace(), "offset", e.Time.Sub(first))
+	}
+	slog.Info("ReplayEvents time taken", "duration", time.Since(begin), "numReplayed", numReplayed)
+	return nil
+}
+
+func (g *GardenerShootCopier) replayEvent(ctx context.Context, u *KindUploader, e api.ObjEvent) (err error) {
+	obj := e.Object.DeepCopy()
+	err = CleanObj(obj)
+	if err != nil {
+		return
+	}
+	if g.cfg.DummySecretData {
+		err = GenDummySecretData(obj)
+		if err != nil {
+			return
+		}
+	}
+	switch e.Type {
+	case watch.Added:
+		return u.Upload(ctx, obj)
+	case watch.Modified:
+		// pod specs are largely immutable and the node assignment is left to the target scheduler
+		if obj.GetKind() == "Pod" {
+			slog.Debug("Skipping pod modification.", "name", obj.GetName(), "namespace", obj.GetNamespace())
+			return nil
+		}
+		return u.Apply(ctx, obj)
+	case watch.Deleted:
+		return u.Delete(ctx, obj)
+	default:
+		slog.Warn("Skipping unknown event type.", "type", e.Type, "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+}
+
+// LoadObjEvents loads the events from the event log at the given path ordered by time.
+func LoadObjEvents(path string) (events []api.ObjEvent, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		err = fmt.Errorf("%w: failed to open event log %q: %w", api.ErrLoadObj, path, err)
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	decoder := json.NewDecoder(f)
+	for {
+		var e api.ObjEvent
+		err = decoder.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			err = nil
+			break
+		}
+		if err != nil {
+			err = fmt.Errorf("%w: failed to decode event %d of event log %q: %w", api.ErrLoadObj, len(events), path, err)
+			return
+		}
+		if e.Object == nil {
+			err = fmt.Errorf("%w: event %d of event log %q has no object", api.ErrLoadObj, len(events), path)
+			return
+		}
+		events = append(events, e)
+	}
+	slices.SortStableFunc(events, func(a, b api.ObjEvent) int {
+		return a.Time.Compare(b.Time)
+	})
+	slog.Info("Loaded events.", "path", path, "numEvents", len(events))
+	return
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"github.com/elankath/kcpcl/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoadObjEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EventLogFilename)
+	recorder, err := newEventRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("p1")
+	pod.SetNamespace("default")
+	// record out of order to verify that events are loaded ordered by time
+	for _, e := range []api.ObjEvent{
+		{Time: now.Add(time.Minute), Type: watch.Deleted, GVR: "v1/pods", Object: pod},
+		{Time: now, Type: watch.Added, GVR: "v1/pods", Object: pod},
+	} {
+		err = recorder.Record(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := LoadObjEvents(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Type != watch.Added || events[1].Type != watch.Deleted {
+		t.Errorf("expected events ordered by time, got %s, %s", events[0].Type, events[1].Type)
+	}
+	if events[0].Object.GetKind() != "Pod" || events[0].Object.GetName() != "p1" {
+		t.Errorf("unexpected object %s %q", events[0].Object.GetKind(), events[0].Object.GetName())
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	ctx := context.Background()
+	baseObjDir := t.TempDir()
+	recorder, err := newEventRecorder(filepath.Join(baseObjDir, EventLogFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cm := newObj("ConfigMap", "default", "cm")
+	for _, e := range []api.ObjEvent{
+		{Time: first, Type: watch.Added, GVR: "v1/configmaps", Object: newObj("ConfigMap", "default", "before-start")},
+		{Time: first.Add(10 * time.Second), Type: watch.Added, GVR: "v1/configmaps", Object: cm},
+		{Time: first.Add(20 * time.Second), Type: watch.Modified, GVR: "v1/pods", Object: newPod("p", "n1", "")},
+		{Time: first.Add(30 * time.Second), Type: watch.Modified, GVR: "v1/configmaps", Object: cm},
+		{Time: first.Add(40 * time.Second), Type: watch.Deleted, GVR: "v1/configmaps", Object: cm},
+		{Time: first.Add(time.Minute), Type: watch.Added, GVR: "v1/configmaps", Object: newObj("ConfigMap", "default", "after-stop")},
+	} {
+		if err = recorder.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var delays []time.Duration
+	replaySleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	t.Cleanup(func() {
+		replaySleep = sleepContext
+	})
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	// the object tracker of the fake client does not support server-side apply
+	dynamicClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, cm, nil
+	})
+	discoveryClient := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+		}},
+	}}}
+	g := &GardenerShootCopier{dynamicClient: dynamicClient, discoveryClient: discoveryClient}
+
+	err = g.ReplayEvents(ctx, baseObjDir, api.ReplayOpts{Speed: 10, Start: 10 * time.Second, Stop: 45 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Duration{time.Second, time.Second, time.Second}; !slices.Equal(delays, want) {
+		t.Errorf("expected delays scaled by speed %v, got %v", want, delays)
+	}
+	var got []string
+	for _, a := range dynamicClient.Actions() {
+		got = append(got, a.GetVerb()+" "+a.GetResource().Resource)
+	}
+	// events before start and after stop are skipped and pod modifications are left to the target scheduler
+	if want := []string{"create configmaps", "patch configmaps", "delete configmaps"}; !slices.Equal(got, want) {
+		t.Errorf("expected actions %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"sigs.k8s.io/yaml"
+)
+
+// writeReport writes the given report to w in the given format. Text and table formats are written using textFn.
+func writeReport(w io.Writer, format api.OutputFormat, report any, textFn func(w io.Writer) error) error {
+	switch format {
+	case api.OutputText, api.OutputTable, "":
+		return textFn(w)
+	case api.OutputJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("cannot marshal report to JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case api.OutputYAML:
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("cannot marshal report to YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("%w: %q", api.ErrInvalidOutputFormat, format)
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// retry calls fn till it succeeds, fails with an error that is not retriable or policy.MaxAttempts is reached. It
+// returns the number of retries along with the error of the last attempt.
+func retry(ctx context.Context, policy api.RetryPolicy, op string, retriable func(error) bool, fn func() error) (retries int, err error) {
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= policy.MaxAttempts || !retriable(err) {
+			return
+		}
+		delay := retryDelay(policy, attempt, err)
+		slog.Warn("Retrying after transient error.", "op", op, "attempt", attempt, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		retries++
+	}
+}
+
+// listWithRetry lists the objects of the given resource retrying transient errors according to the retry policy of the
+// copier. Concurrent lists are limited by the adaptive limiter of the copier if set.
+func (g *GardenerShootCopier) listWithRetry(ctx context.Context, ri dynamic.ResourceInterface, opts metav1.ListOptions) (objList *unstructured.UnstructuredList, retries int, err error) {
+	retries, err = retry(ctx, g.cfg.Retry, "list", isTransient, func() (err error) {
+		if err = g.limiter.acquire(ctx); err != nil {
+			return
+		}
+		begin := time.Now()
+		objList, err = ri.List(ctx, opts)
+		g.limiter.release(time.Since(begin), err)
+		return
+	})
+	return
+}
+
+// retryDelay returns the exponential backoff with jitter before the retry following the given attempt. A longer delay
+// suggested by the server through Retry-After takes precedence.
+func retryDelay(policy api.RetryPolicy, attempt int, err error) time.Duration {
+	backoff := policy.InitialBackoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || backoff < policy.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	backoff = wait.Jitter(backoff, 0.5)
+	if policy.MaxBackoff > 0 {
+		backoff = min(backoff, policy.MaxBackoff)
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		backoff = max(backoff, time.Duration(seconds)*time.Second)
+	}
+	return backoff
+}
+
+// isTransient returns whether the given error of an API request is likely to go away when the request is retried.
+func isTransient(err error) bool {
+	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return status.Status().Code >= 500
+	}
+	if utilnet.IsConnectionReset(err) || utilnet.IsConnectionRefused(err) || utilnet.IsProbableEOF(err) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isUnprocessed returns whether the given error of an API request guarantees that the request was not processed, so
+// that requests which are not idempotent like creates can be retried safely. A create that timed out or failed with a
+// server error may have been persisted nevertheless and would fail with AlreadyExists when retried.
+func isUnprocessed(err error) bool {
+	return apierrors.IsTooManyRequests(err) || utilnet.IsConnectionRefused(err)
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := api.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	gr := schema.GroupResource{Resource: "pods"}
+	transientErrs := []error{
+		apierrors.NewTooManyRequests("slow down", 0),
+		apierrors.NewServiceUnavailable("unavailable"),
+		apierrors.NewTimeoutError("timeout", 0),
+		fmt.Errorf("wrapped: %w", syscall.ECONNRESET),
+	}
+	for _, transientErr := range transientErrs {
+		calls := 0
+		retries, err := retry(context.Background(), policy, "test", isTransient, func() error {
+			calls++
+			if calls < 3 {
+				return transientErr
+			}
+			return nil
+		})
+		if err != nil || retries != 2 {
+			t.Errorf("expected success after 2 retries of %v, got retries=%d err=%v", transientErr, retries, err)
+		}
+	}
+
+	calls := 0
+	retries, err := retry(context.Background(), policy, "test", isTransient, func() error {
+		calls++
+		return apierrors.NewAlreadyExists(gr, "p")
+	})
+	if !apierrors.IsAlreadyExists(err) || retries != 0 || calls != 1 {
+		t.Errorf("expected no retry of permanent error, got retries=%d calls=%d err=%v", retries, calls, err)
+	}
+
+	retries, err = retry(context.Background(), policy, "test", isTransient, func() error {
+		return apierrors.NewInternalError(fmt.Errorf("boom"))
+	})
+	if !apierrors.IsInternalError(err) || retries != 2 {
+		t.Errorf("expected error after max attempts, got retries=%d err=%v", retries, err)
+	}
+
+	for _, err := range []error{apierrors.NewTooManyRequests("slow down", 0), fmt.Errorf("wrapped: %w", syscall.ECONNREFUSED)} {
+		if !isUnprocessed(err) {
+			t.Errorf("expected create failing with %v to be retried", err)
+		}
+	}
+	for _, err := range []error{apierrors.NewTimeoutError("timeout", 0), apierrors.NewInternalError(fmt.Errorf("boom")), fmt.Errorf("wrapped: %w", syscall.ECONNRESET)} {
+		if isUnprocessed(err) {
+			t.Errorf("expected create failing with %v not to be retried", err)
+		}
+	}
+
+	if d := retryDelay(policy, 5, apierrors.NewTooManyRequests("slow down", 2)); d != 2*time.Second {
+		t.Errorf("expected Retry-After to take precedence, got %s", d)
+	}
+	if d := retryDelay(policy, 5, apierrors.NewServiceUnavailable("unavailable")); d > policy.MaxBackoff {
+		t.Errorf("expected backoff capped at %s, got %s", policy.MaxBackoff, d)
+	}
+}
@@ -0,0 +1,213 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"log/slog"
+	"maps"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	podsGVR = corev1.SchemeGroupVersion.WithResource("pods")
+	// leadingCountRegex matches the node count prefixing each reason in the message of the PodScheduled condition.
+	// Ex: "3 Insufficient cpu"
+	leadingCountRegex = regexp.MustCompile(`^\d+ `)
+)
+
+func (g *GardenerShootCopier) WaitScheduled(ctx context.Context, baseObjDir string, timeout time.Duration) (report api.SchedulingReport, err error) {
+	objs, err := loadResourceDirObjs(filepath.Join(baseObjDir, resourceDirName(podsGVR)))
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrWaitScheduled, err)
+		return
+	}
+	expected := make(map[string]struct{}, len(objs))
+	for _, obj := range objs {
+		expected[cache.NewObjectName(obj.GetNamespace(), obj.GetName()).String()] = struct{}{}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(g.dynamicClient, 0)
+	informer := factory.ForResource(podsGVR).Informer()
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { notify() },
+		UpdateFunc: func(any, any) { notify() },
+	})
+	if err != nil {
+		err = fmt.Errorf("%w: cannot add pod event handler: %w", api.ErrWaitScheduled, err)
+		return
+	}
+	factory.Start(waitCtx.Done())
+	defer factory.Shutdown()
+
+	slog.Info("Waiting for pods to be scheduled.", "numPods", len(expected), "timeout", timeout)
+	synced := cache.WaitForCacheSync(waitCtx.Done(), informer.HasSynced)
+	for {
+		if synced {
+			report, err = schedulingReport(expected, informer.GetStore().List())
+			if err != nil {
+				return
+			}
+			if report.NumPending == 0 {
+				return
+			}
+			slog.Info("Pods pending scheduling.", "numPending", report.NumPending, "numBound", report.NumBound, "numUnschedulable", report.NumUnschedulable)
+		}
+		select {
+		case <-changed:
+		case <-waitCtx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				err = fmt.Errorf("%w: %w", api.ErrWaitScheduled, ctx.Err())
+				return
+			}
+			if !synced {
+				err = fmt.Errorf("%w: cannot sync pod informer cache within %s", api.ErrWaitScheduled, timeout)
+				return
+			}
+			report.TimedOut = true
+			slog.Warn("Timed out waiting for pods to be scheduled.", "timeout", timeout, "numPending", report.NumPending)
+			return
+		}
+	}
+}
+
+// schedulingReport summarizes the scheduling state of the expected pods, keyed by namespace/name, among the given
+// pods. Expected pods which are missing are counted as pending.
+func schedulingReport(expected map[string]struct{}, objs []any) (report api.SchedulingReport, err error) {
+	report.NumPods = len(expected)
+	podsByReason := make(map[string][]string)
+	var durations []time.Duration
+	seen := 0
+	for _, o := range objs {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		key := cache.NewObjectName(u.GetNamespace(), u.GetName()).String()
+		if _, ok = expected[key]; !ok {
+			continue
+		}
+		seen++
+		var pod corev1.Pod
+		if err = fromUnstructured(u, &pod); err != nil {
+			err = fmt.Errorf("%w: %w", api.ErrWaitScheduled, err)
+			return
+		}
+		cond := podScheduledCondition(&pod)
+		switch {
+		case pod.Spec.NodeName != "":
+			report.NumBound++
+			if cond != nil && cond.Status == corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+				durations = append(durations, max(0, cond.LastTransitionTime.Sub(pod.CreationTimestamp.Time)))
+			}
+		case cond != nil && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable:
+			report.NumUnschedulable++
+			for _, reason := range unschedulableReasons(cond.Message) {
+				podsByReason[reason] = append(podsByReason[reason], key)
+			}
+		default:
+			report.NumPending++
+		}
+	}
+	report.NumPending += len(expected) - seen
+	report.Unschedulable = unschedulableByReason(podsByReason)
+	report.TimeToSchedule = durationPercentiles(durations)
+	return
+}
+
+// unschedulableByReason returns the given unschedulable pods keyed by reason ordered by descending number of pods.
+func unschedulableByReason(podsByReason map[string][]string) (unschedulable []api.UnschedulableReason) {
+	for _, reason := range slices.Sorted(maps.Keys(podsByReason)) {
+		pods := podsByReason[reason]
+		slices.Sort(pods)
+		unschedulable = append(unschedulable, api.UnschedulableReason{Reason: reason, Pods: pods})
+	}
+	slices.SortStableFunc(unschedulable, func(a, b api.UnschedulableReason) int {
+		return cmp.Compare(len(b.Pods), len(a.Pods))
+	})
+	return
+}
+
+func podScheduledCondition(pod *corev1.Pod) *corev1.PodCondition {
+	for i, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// unschedulableReasons splits the message of an unschedulable PodScheduled condition into its reasons without node
+// counts and preemption details. Ex: "0/3 nodes are available: 1 node(s) had untolerated taint {a: b}, 2 Insufficient
+// cpu. preemption: ..." yields "node(s) had untolerated taint {a: b}" and "Insufficient cpu".
+func unschedulableReasons(msg string) []string {
+	if msg == "" {
+		return []string{corev1.PodReasonUnschedulable}
+	}
+	if _, after, ok := strings.Cut(msg, "are available: "); ok {
+		msg = after
+	}
+	msg, _, _ = strings.Cut(msg, " preemption:")
+	msg = strings.TrimSpace(strings.TrimRight(msg, ". "))
+	var reasons []string
+	for _, part := range strings.Split(msg, ", ") {
+		part = leadingCountRegex.ReplaceAllString(strings.TrimSpace(part), "")
+		if part != "" {
+			reasons = append(reasons, part)
+		}
+	}
+	return reasons
+}
+
+func durationPercentiles(durations []time.Duration) (p api.DurationPercentiles) {
+	if len(durations) == 0 {
+		return
+	}
+	slices.Sort(durations)
+	percentile := func(q float64) time.Duration {
+		// nearest-rank percentile
+		idx := int(math.Ceil(q*float64(len(durations)))) - 1
+		return durations[max(0, min(idx, len(durations)-1))]
+	}
+	p.P50, p.P90, p.P99 = percentile(0.5), percentile(0.9), percentile(0.99)
+	p.Max = durations[len(durations)-1]
+	return
+}
+
+// WriteSchedulingReport writes the given scheduling report to w in the given format.
+func WriteSchedulingReport(w io.Writer, report api.SchedulingReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "Pods: total=%d bound=%d unschedulable=%d pending=%d timedOut=%t\n",
+			report.NumPods, report.NumBound, report.NumUnschedulable, report.NumPending, report.TimedOut)
+		tts := report.TimeToSchedule
+		_, _ = fmt.Fprintf(w, "Time to schedule: p50=%s p90=%s p99=%s max=%s\n", tts.P50, tts.P90, tts.P99, tts.Max)
+		for _, r := range report.Unschedulable {
+			_, _ = fmt.Fprintf(w, "UNSCHEDULABLE (%d pods): %s\n", len(r.Pods), r.Reason)
+			for _, p := range r.Pods {
+				_, _ = fmt.Fprintf(w, "  %s\n", p)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newSchedulingPod returns a pod created at the given time whose PodScheduled condition has the given status, reason
+// and message and transitioned scheduledAfter its creation. The condition is left out if status is empty.
+func newSchedulingPod(created time.Time, name, nodeName, status, reason, message string, scheduledAfter time.Duration) any {
+	pod := newPod(name, nodeName, "")
+	pod.SetCreationTimestamp(metav1.NewTime(created))
+	if status != "" {
+		pod.Object["status"] = map[string]any{"conditions": []any{map[string]any{
+			"type":               "PodScheduled",
+			"status":             status,
+			"reason":             reason,
+			"message":            message,
+			"lastTransitionTime": created.Add(scheduledAfter).Format(time.RFC3339),
+		}}}
+	}
+	return pod
+}
+
+func TestSchedulingReport(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	msg := "0/3 nodes are available: 1 node(s) had untolerated taint {dedicated: x}, 2 Insufficient cpu. preemption: 0/3 nodes are available: 3 No preemption victims found for incoming pod.."
+	objs := []any{
+		newSchedulingPod(created, "b1", "n1", "True", "", "", 1*time.Second),
+		newSchedulingPod(created, "b2", "n1", "True", "", "", 3*time.Second),
+		newSchedulingPod(created, "u1", "", "False", "Unschedulable", msg, 0),
+		newSchedulingPod(created, "u2", "", "False", "Unschedulable", "0/3 nodes are available: 3 Insufficient cpu.", 0),
+		newSchedulingPod(created, "p1", "", "", "", "", 0),
+		newSchedulingPod(created, "other", "n1", "True", "", "", 0),
+	}
+	expected := map[string]struct{}{"default/b1": {}, "default/b2": {}, "default/u1": {}, "default/u2": {}, "default/p1": {}, "default/missing": {}}
+
+	report, err := schedulingReport(expected, objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumPods != 6 || report.NumBound != 2 || report.NumUnschedulable != 2 || report.NumPending != 2 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if len(report.Unschedulable) != 2 {
+		t.Fatalf("expected 2 unschedulable reasons, got %+v", report.Unschedulable)
+	}
+	if r := report.Unschedulable[0]; r.Reason != "Insufficient cpu" || !slices.Equal(r.Pods, []string{"default/u1", "default/u2"}) {
+		t.Errorf("unexpected top unschedulable reason: %+v", r)
+	}
+	if r := report.Unschedulable[1]; r.Reason != "node(s) had untolerated taint {dedicated: x}" {
+		t.Errorf("unexpected unschedulable reason: %+v", r)
+	}
+	if tts := report.TimeToSchedule; tts.P50 != time.Second || tts.Max != 3*time.Second {
+		t.Errorf("unexpected time to schedule: %+v", tts)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	redactedChar = "*"
+	dummyChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// lastAppliedConfigAnnotation can carry the full secret payload when secrets are managed via 'kubectl apply'.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// RedactSecretData replaces every value in the data and stringData of the given Secret with a placeholder of the same
+// length, keeping the keys intact. The object is annotated with api.AnnotationSecretDataRedacted. Non-secret objects are
+// left untouched.
+func RedactSecretData(obj *unstructured.Unstructured) error {
+	if obj.GetKind() != "Secret" {
+		return nil
+	}
+	err := replaceSecretValues(obj, func(n int) string { return strings.Repeat(redactedChar, n) })
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	delete(annotations, lastAppliedConfigAnnotation)
+	annotations[api.AnnotationSecretDataRedacted] = "true"
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// GenDummySecretData replaces every value in the data and stringData of the given Secret with random alphanumeric
+// values of the same length so that the secret can be created in the target cluster. Non-secret objects are left untouched.
+func GenDummySecretData(obj *unstructured.Unstructured) error {
+	if obj.GetKind() != "Secret" {
+		return nil
+	}
+	return replaceSecretValues(obj, genDummyValue)
+}
+
+func redactSecretList(objList *unstructured.UnstructuredList) error {
+	for i := range objList.Items {
+		err := RedactSecretData(&objList.Items[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceSecretValues replaces values of the secret data and stringData using genFn which is invoked with the length
+// of the decoded value.
+func replaceSecretValues(obj *unstructured.Unstructured, genFn func(n int) string) error {
+	data, found, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return fmt.Errorf("%w: cannot read data of secret %q in namespace %q: %w", api.ErrSecretData, obj.GetName(), obj.GetNamespace(), err)
+	}
+	if found {
+		for k, v := range data {
+			decoded, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return fmt.Errorf("%w: cannot decode key %q of secret %q in namespace %q: %w", api.ErrSecretData, k, obj.GetName(), obj.GetNamespace(), err)
+			}
+			data[k] = base64.StdEncoding.EncodeToString([]byte(genFn(len(decoded))))
+		}
+		err = unstructured.SetNestedStringMap(obj.Object, data, "data")
+		if err != nil {
+			return fmt.Errorf("%w: cannot set data of secret %q in namespace %q: %w", api.ErrSecretData, obj.GetName(), obj.GetNamespace(), err)
+		}
+	}
+	stringData, found, err := unstructured.NestedStringMap(obj.Object, "stringData")
+	if err != nil {
+		return fmt.Errorf("%w: cannot read stringData of secret %q in namespace %q: %w", api.ErrSecretData, obj.GetName(), obj.GetNamespace(), err)
+	}
+	if found {
+		for k, v := range stringData {
+			stringData[k] = genFn(len(v))
+		}
+		err = unstructured.SetNestedStringMap(obj.Object, stringData, "stringData")
+		if err != nil {
+			return fmt.Errorf("%w: cannot set stringData of secret %q in namespace %q: %w", api.ErrSecretData, obj.GetName(), obj.GetNamespace(), err)
+		}
+	}
+	slog.Debug("Replaced secret values.", "name", obj.GetName(), "namespace", obj.GetNamespace(), "numKeys", len(data)+len(stringData))
+	return nil
+}
+
+func genDummyValue(n int) string {
+	var sb strings.Builder
+	sb.Grow(n)
+	for range n {
+		sb.WriteByte(dummyChars[rand.IntN(len(dummyChars))])
+	}
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"encoding/base64"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestRedactSecretData(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]any{
+			"name":      "creds",
+			"namespace": "default",
+			"annotations": map[string]any{
+				lastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`,
+			},
+		},
+		"data": map[string]any{
+			"password": base64.StdEncoding.EncodeToString([]byte("secret")),
+		},
+		"stringData": map[string]any{
+			"token": "abc",
+		},
+	}}
+	err := RedactSecretData(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, _ := unstructured.NestedStringMap(secret.Object, "data")
+	decoded, err := base64.StdEncoding.DecodeString(data["password"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != "******" {
+		t.Errorf("expected redacted password of same length, got %q", decoded)
+	}
+	stringData, _, _ := unstructured.NestedStringMap(secret.Object, "stringData")
+	if stringData["token"] != "***" {
+		t.Errorf("expected redacted token of same length, got %q", stringData["token"])
+	}
+	annotations := secret.GetAnnotations()
+	if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+		t.Errorf("expected %q annotation to be removed", lastAppliedConfigAnnotation)
+	}
+	if annotations[api.AnnotationSecretDataRedacted] != "true" {
+		t.Errorf("expected %q annotation to be set", api.AnnotationSecretDataRedacted)
+	}
+
+	err = GenDummySecretData(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, _ = unstructured.NestedStringMap(secret.Object, "data")
+	decoded, _ = base64.StdEncoding.DecodeString(data["password"])
+	if len(decoded) != 6 || string(decoded) == "******" {
+		t.Errorf("expected dummy password of same length, got %q", decoded)
+	}
+}
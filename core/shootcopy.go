@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"github.com/elankath/kcpcl/core/clientutil"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// nodeGroupResources are the resources of node objects or objects bound to nodes which are never copied from one
+// shoot to another.
+var nodeGroupResources = []schema.GroupResource{
+	{Group: "", Resource: "nodes"},
+	{Group: "storage.k8s.io", Resource: "csinodes"},
+	{Group: "storage.k8s.io", Resource: "volumeattachments"},
+	{Group: "storage.k8s.io", Resource: "csistoragecapacities"},
+}
+
+func (g *GardenerShootCopier) CopyShootObjects(ctx context.Context, gvrList []schema.GroupVersionResource) (report api.ShootCopyReport, err error) {
+	report.Source, report.Target, report.DryRun = g.GetShootCoordinate(), g.cfg.TargetShoot, g.cfg.DryRun
+	if len(gvrList) == 0 {
+		err = fmt.Errorf("%w: %w", api.ErrCopyFailed, api.ErrMissingGVRs)
+		return
+	}
+	if report.Source == (api.ShootCoords{}) || report.Target.Name == "" {
+		err = fmt.Errorf("%w: %w: source and target must be given by shoot coordinates", api.ErrCopyFailed, api.ErrUnsafeCopy)
+		return
+	}
+	if report.Source == report.Target {
+		err = fmt.Errorf("%w: %w: source and target shoot %s are the same", api.ErrCopyFailed, api.ErrUnsafeCopy, report.Source)
+		return
+	}
+	guard := &shootCopyGuard{forceKubeSystem: g.cfg.ForceKubeSystem, dryRun: g.cfg.DryRun}
+	slog.Info("Copying objects from shoot to shoot.", "source", report.Source, "target", report.Target, "dryRun", g.cfg.DryRun, "forceKubeSystem", g.cfg.ForceKubeSystem)
+	err = g.copyObjects(ctx, gvrList, "", guard)
+	report.Copied, report.Skipped = guard.copied, guard.skipped
+	slices.SortFunc(report.Copied, compareObjIDs)
+	slices.SortFunc(report.Skipped, func(a, b api.SkippedObj) int {
+		return compareObjIDs(a.ID, b.ID)
+	})
+	return
+}
+
+func (g *GardenerShootCopier) initTargetShootClients(ctx context.Context) error {
+	coords := g.cfg.TargetShoot
+	if g.gardenClient == nil {
+		return fmt.Errorf("%w: %w", api.ErrCreateGardenClient, api.ErrMissingKubeConfig)
+	}
+	kubeConfig, _, err := GenShootKubeConfig(ctx, g.gardenClient, coords, AdminAccess, shootKubeConfigExpiration)
+	if err != nil {
+		return fmt.Errorf("%w: %w", api.ErrCreateKubeClient, err)
+	}
+	qps, burst := g.cfg.ClientRateLimit()
+	g.targetDynamicClient, g.targetDiscoveryClient, err = clientutil.CreateDynamicAndDiscoveryClientsFromKubeConfig(kubeConfig, qps, burst)
+	if err != nil {
+		return fmt.Errorf("%w: cannot create kube clients for target shoot %s: %w", api.ErrCreateKubeClient, coords, err)
+	}
+	return nil
+}
+
+// shootCopyGuard decides which objects are copied from one shoot to another and records the decisions. It is safe
+// for concurrent use.
+type shootCopyGuard struct {
+	forceKubeSystem bool
+	dryRun          bool
+	mu              sync.Mutex
+	copied          []api.ObjID
+	skipped         []api.SkippedObj
+}
+
+// admit returns whether the given object should be uploaded to the target shoot. In a dry-run, objects that would be
+// copied are recorded but not admitted.
+func (c *shootCopyGuard) admit(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) bool {
+	id := api.ObjID{GVR: api.FormatGVR(gvr), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	reason := c.skipReason(gvr, obj)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if reason != "" {
+		slog.Info("Skipping object.", "id", id, "reason", reason)
+		c.skipped = append(c.skipped, api.SkippedObj{ID: id, Reason: reason})
+		return false
+	}
+	c.copied = append(c.copied, id)
+	return !c.dryRun
+}
+
+func (c *shootCopyGuard) skipReason(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) string {
+	if slices.Contains(nodeGroupResources, gvr.GroupResource()) {
+		return "node object"
+	}
+	if nodeName, _, _ := unstructured.NestedString(obj.Object, "spec", "nodeName"); nodeName != "" {
+		return fmt.Sprintf("bound to node %q", nodeName)
+	}
+	if c.forceKubeSystem {
+		return ""
+	}
+	if obj.GetNamespace() == metav1.NamespaceSystem || (gvr.GroupResource() == namespacesGR && obj.GetName() == metav1.NamespaceSystem) {
+		return "in kube-system"
+	}
+	return ""
+}
+
+var namespacesGR = corev1.SchemeGroupVersion.WithResource("namespaces").GroupResource()
+
+// WriteShootCopyReport writes the given shoot copy report to w in the given format.
+func WriteShootCopyReport(w io.Writer, report api.ShootCopyReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		action := "COPIED"
+		if report.DryRun {
+			action = "WOULD COPY"
+		}
+		for _, id := range report.Copied {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", action, id)
+		}
+		for _, s := range report.Skipped {
+			_, _ = fmt.Fprintf(w, "SKIPPED: %s: %s\n", s.ID, s.Reason)
+		}
+		_, err := fmt.Fprintf(w, "Summary: source=%s target=%s dryRun=%t copied=%d skipped=%d\n",
+			report.Source, report.Target, report.DryRun, len(report.Copied), len(report.Skipped))
+		return err
+	})
+}
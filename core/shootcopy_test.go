@@ -0,0 +1,37 @@
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func TestShootCopyGuard(t *testing.T) {
+	configMapsGVR := corev1.SchemeGroupVersion.WithResource("configmaps")
+	guard := &shootCopyGuard{}
+	if !guard.admit(configMapsGVR, newObj("ConfigMap", "default", "cm")) {
+		t.Errorf("expected configmap in default namespace to be admitted")
+	}
+	if guard.admit(configMapsGVR, newObj("ConfigMap", "kube-system", "cm")) {
+		t.Errorf("expected configmap in kube-system to be skipped")
+	}
+	if guard.admit(namespacesGR.WithVersion("v1"), newObj("Namespace", "", "kube-system")) {
+		t.Errorf("expected kube-system namespace to be skipped")
+	}
+	if guard.admit(podsGVR, newPod("bound", "n1", "")) {
+		t.Errorf("expected pod bound to node to be skipped")
+	}
+	if guard.admit(nodesGVR, newObj("Node", "", "n1")) {
+		t.Errorf("expected node to be skipped")
+	}
+	if len(guard.copied) != 1 || len(guard.skipped) != 4 {
+		t.Errorf("unexpected recorded objects: copied=%v skipped=%v", guard.copied, guard.skipped)
+	}
+
+	forced := &shootCopyGuard{forceKubeSystem: true, dryRun: true}
+	if forced.admit(configMapsGVR, newObj("ConfigMap", "kube-system", "cm")) {
+		t.Errorf("expected no object to be admitted in a dry-run")
+	}
+	if len(forced.copied) != 1 || len(forced.skipped) != 0 {
+		t.Errorf("expected forced kube-system configmap to be recorded as copied: copied=%v skipped=%v", forced.copied, forced.skipped)
+	}
+}
@@ -0,0 +1,340 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	schedulerscheme "k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// simulationPollInterval is the interval in which the simulation checks whether the scheduler is idle.
+	simulationPollInterval = 10 * time.Millisecond
+	// reasonPreempted is the unschedulable reason of pods that were scheduled and then preempted.
+	reasonPreempted = "preempted by a higher priority pod"
+)
+
+// SimulateObjDir schedules the pods of the given obj dir onto its nodes by running kube-scheduler in-process with the
+// profiles of the given configuration against an in-memory API server seeded with the objects of the obj dir. Pods are
+// scheduled by the profile of their scheduler name unless schedulerName is given. The resulting placement is compared
+// against the original placement of the pods.
+//
+// The scheduler runs every plugin of the profiles, including preemption, but neither calls extenders nor retries pods
+// after a backoff: an unschedulable pod is only retried when a later cluster event, like the binding or preemption of
+// another pod, may make it schedulable.
+func SimulateObjDir(baseObjDir string, schedulerConfig []byte, schedulerName string) (report api.SimulationReport, err error) {
+	var objs []*unstructured.Unstructured
+	err = walkObjFiles(baseObjDir, func(path string) error {
+		obj, err := LoadObj(path)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, obj)
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		return
+	}
+	klog.SetSlogLogger(slog.Default())
+	return simulateScheduling(context.Background(), schedulerConfig, schedulerName, objs)
+}
+
+// loadSchedulerConfiguration decodes the given kube-scheduler configuration with the defaults applied by kube-scheduler
+// and validates it.
+func loadSchedulerConfiguration(schedulerConfig []byte) (*schedulerconfig.KubeSchedulerConfiguration, error) {
+	obj, _, err := schedulerscheme.Codecs.UniversalDecoder().Decode(schedulerConfig, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", api.ErrSimulateFailed, api.ErrInvalidSchedulerConfig, err)
+	}
+	cfg, ok := obj.(*schedulerconfig.KubeSchedulerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: unexpected object %T", api.ErrSimulateFailed, api.ErrInvalidSchedulerConfig, obj)
+	}
+	if err = validation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", api.ErrSimulateFailed, api.ErrInvalidSchedulerConfig, err)
+	}
+	return cfg, nil
+}
+
+// simulateScheduling seeds a fake clientset with the given objects and schedules its pods with a kube-scheduler of the
+// given configuration. As on upload, the node assignment of the given pods is ignored. It is compared against the
+// simulated placement. Objects of kinds unknown to client-go are skipped.
+func simulateScheduling(ctx context.Context, schedulerConfig []byte, schedulerName string, objs []*unstructured.Unstructured) (report api.SimulationReport, err error) {
+	cfg, err := loadSchedulerConfiguration(schedulerConfig)
+	if err != nil {
+		return
+	}
+	if len(cfg.Extenders) > 0 {
+		slog.Warn("Extenders are not called by the simulation.", "numExtenders", len(cfg.Extenders))
+	}
+	profileNames := make(map[string]struct{}, len(cfg.Profiles))
+	for _, p := range cfg.Profiles {
+		profileNames[p.SchedulerName] = struct{}{}
+	}
+
+	var nodeObjs, podObjs []*unstructured.Unstructured
+	var typedObjs []runtime.Object
+	pods := make(map[string]*corev1.Pod)
+	for _, o := range objs {
+		typed, err := scheme.Scheme.New(o.GroupVersionKind())
+		if runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		}
+		if err = fromUnstructured(o, typed); err != nil {
+			return report, fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		}
+		// the scheduler cache is keyed by UID which is assigned by the API server
+		if o.GetUID() == "" {
+			typed.(metav1.Object).SetUID(types.UID(o.GetKind() + "/" + cache.NewObjectName(o.GetNamespace(), o.GetName()).String()))
+		}
+		switch t := typed.(type) {
+		case *corev1.Node:
+			nodeObjs = append(nodeObjs, o)
+		case *corev1.Pod:
+			if isPodTerminated(t) {
+				continue
+			}
+			podObjs = append(podObjs, o)
+			t.Spec.NodeName = ""
+			t.Status = corev1.PodStatus{Phase: corev1.PodPending}
+			t.Spec.SchedulerName = cmp.Or(schedulerName, t.Spec.SchedulerName, defaultSchedulerName)
+			pods[cache.MetaObjectToName(t).String()] = t
+		}
+		typedObjs = append(typedObjs, typed)
+	}
+	report.NumPods = len(pods)
+
+	client := fake.NewClientset(typedObjs...)
+	client.PrependReactor("create", "pods", bindPodReactor(client.Tracker()))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	informerFactory := scheduler.NewInformerFactory(client, 0)
+	sched, err := scheduler.New(ctx, client, informerFactory, nil,
+		func(string) events.EventRecorder { return &events.FakeRecorder{} },
+		scheduler.WithProfiles(cfg.Profiles...),
+		scheduler.WithPercentageOfNodesToScore(cfg.PercentageOfNodesToScore),
+		scheduler.WithParallelism(cfg.Parallelism),
+		// pods are not held back in the backoff queue as it is only flushed by a running scheduler
+		scheduler.WithPodInitialBackoffSeconds(0),
+		scheduler.WithPodMaxBackoffSeconds(0),
+	)
+	if err != nil {
+		err = fmt.Errorf("%w: cannot create scheduler: %w", api.ErrSimulateFailed, err)
+		return
+	}
+	recorder := &failureRecorder{reasons: make(map[string][]string)}
+	sched.FailureHandler = recorder.wrap(sched.FailureHandler, sched.SchedulingQueue.Done)
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	if err = sched.WaitForHandlersSync(ctx); err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		return
+	}
+	if err = runScheduler(ctx, sched, func() bool { return isInformerSynced(client, informerFactory.Core().V1().Pods().Lister()) }); err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		return
+	}
+
+	podsByReason := make(map[string][]string)
+	simulated := make([]*unstructured.Unstructured, 0, len(podObjs))
+	for _, o := range podObjs {
+		key := cache.NewObjectName(o.GetNamespace(), o.GetName()).String()
+		obj := o.DeepCopy()
+		unstructured.RemoveNestedField(obj.Object, "spec", "nodeName")
+		var reasons []string
+		current, err := client.CoreV1().Pods(o.GetNamespace()).Get(ctx, o.GetName(), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			reasons = []string{reasonPreempted}
+		case err != nil:
+			return report, fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		case current.Spec.NodeName != "":
+			report.NumScheduled++
+			if err = unstructured.SetNestedField(obj.Object, current.Spec.NodeName, "spec", "nodeName"); err != nil {
+				return report, fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+			}
+		default:
+			reasons = recorder.reasons[key]
+			if _, ok := profileNames[pods[key].Spec.SchedulerName]; !ok {
+				reasons = []string{fmt.Sprintf("no profile for scheduler %q", pods[key].Spec.SchedulerName)}
+			} else if len(reasons) == 0 {
+				reasons = []string{"pod was not scheduled"}
+			}
+		}
+		if len(reasons) > 0 {
+			report.NumUnschedulable++
+			for _, r := range reasons {
+				podsByReason[r] = append(podsByReason[r], key)
+			}
+		}
+		simulated = append(simulated, obj)
+	}
+	report.Unschedulable = unschedulableByReason(podsByReason)
+	report.Placement, err = ComparePlacement(nodeObjs, podObjs, simulated)
+	return
+}
+
+// bindPodReactor returns a reactor for the fake clientset that binds pods by setting their node name, which the object
+// tracker does not do for the binding subresource.
+func bindPodReactor(tracker clienttesting.ObjectTracker) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		create, ok := action.(clienttesting.CreateAction)
+		if !ok || create.GetSubresource() != "binding" {
+			return false, nil, nil
+		}
+		binding, ok := create.GetObject().(*corev1.Binding)
+		if !ok {
+			return false, nil, nil
+		}
+		obj, err := tracker.Get(podsGVR, binding.Namespace, binding.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		pod := obj.(*corev1.Pod).DeepCopy()
+		pod.Spec.NodeName = binding.Target.Name
+		return true, binding, tracker.Update(podsGVR, pod, binding.Namespace)
+	}
+}
+
+// runScheduler runs scheduling cycles until the scheduling queue has no active pods, no pod is being scheduled or
+// bound and synced reports that the informers have observed all bindings and preemptions, so that no further cluster
+// event can move a pod back into the active queue.
+func runScheduler(ctx context.Context, sched *scheduler.Scheduler, synced func() bool) error {
+	ticker := time.NewTicker(simulationPollInterval)
+	defer ticker.Stop()
+	idle := false
+	for {
+		if len(sched.SchedulingQueue.PodsInActiveQ()) > 0 {
+			sched.ScheduleOne(ctx)
+			idle = false
+			continue
+		}
+		if len(sched.SchedulingQueue.InFlightPods()) == 0 && synced() {
+			// the queue is only considered drained if it stays so for a poll interval as the informers notify the
+			// queue after updating their stores
+			if idle {
+				return nil
+			}
+			idle = true
+		} else {
+			idle = false
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isInformerSynced reports whether the pods listed by the informer have the same node assignment as the pods of the
+// given clientset.
+func isInformerSynced(client *fake.Clientset, lister corelisters.PodLister) bool {
+	podList, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return false
+	}
+	observed, err := lister.List(labels.Everything())
+	if err != nil || len(observed) != len(podList.Items) {
+		return false
+	}
+	nodeNames := make(map[string]string, len(observed))
+	for _, p := range observed {
+		nodeNames[cache.MetaObjectToName(p).String()] = p.Spec.NodeName
+	}
+	for _, p := range podList.Items {
+		if nodeName, ok := nodeNames[cache.MetaObjectToName(&p).String()]; !ok || nodeName != p.Spec.NodeName {
+			return false
+		}
+	}
+	return true
+}
+
+// failureRecorder records the reasons of the last scheduling failure of each pod.
+type failureRecorder struct {
+	mu      sync.Mutex
+	reasons map[string][]string
+}
+
+// wrap returns a failure handler that records the reasons of the failure before calling the given handler. Scheduler
+// errors, unlike unschedulable pods, are not handed over to the given handler as it would retry the pod immediately.
+// done is called for them instead to remove the pod from the scheduling queue.
+func (r *failureRecorder) wrap(handler scheduler.FailureHandlerFn, done func(uid types.UID)) scheduler.FailureHandlerFn {
+	return func(ctx context.Context, fwk framework.Framework, podInfo *framework.QueuedPodInfo, status *framework.Status, nominatingInfo *framework.NominatingInfo, start time.Time) {
+		r.mu.Lock()
+		r.reasons[cache.MetaObjectToName(podInfo.Pod).String()] = statusReasons(status)
+		r.mu.Unlock()
+		if !status.IsRejected() {
+			done(podInfo.Pod.UID)
+			return
+		}
+		handler(ctx, fwk, podInfo, status, nominatingInfo, start)
+	}
+}
+
+// statusReasons returns the sorted reasons why the nodes were rejected for a pod with the given failure status, like
+// "Insufficient cpu", without the node counts of the status message.
+func statusReasons(status *framework.Status) []string {
+	var fitErr *framework.FitError
+	if !errors.As(status.AsError(), &fitErr) {
+		return []string{cmp.Or(status.Message(), status.Code().String())}
+	}
+	reasons := sets.New[string]()
+	fitErr.Diagnosis.NodeToStatus.ForEachExplicitNode(func(_ string, s *framework.Status) {
+		reasons.Insert(s.Reasons()...)
+	})
+	if fitErr.Diagnosis.NodeToStatus.Len() < fitErr.NumAllNodes {
+		reasons.Insert(fitErr.Diagnosis.NodeToStatus.AbsentNodesStatus().Reasons()...)
+	}
+	if fitErr.Diagnosis.PreFilterMsg != "" {
+		reasons.Insert(fitErr.Diagnosis.PreFilterMsg)
+	}
+	if reasons.Len() == 0 {
+		reasons.Insert(fitErr.Error())
+	}
+	return sets.List(reasons)
+}
+
+// WriteSimulationReport writes the given simulation report to w in the given format.
+func WriteSimulationReport(w io.Writer, report api.SimulationReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		_, _ = fmt.Fprintf(w, "Pods: total=%d scheduled=%d unschedulable=%d\n", report.NumPods, report.NumScheduled, report.NumUnschedulable)
+		for _, r := range report.Unschedulable {
+			_, _ = fmt.Fprintf(w, "UNSCHEDULABLE (%d pods): %s\n", len(r.Pods), r.Reason)
+			for _, p := range r.Pods {
+				_, _ = fmt.Fprintf(w, "  %s\n", p)
+			}
+		}
+		_, _ = fmt.Fprintln(w)
+		return WritePlacementReport(w, report.Placement, format)
+	})
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestSimulateScheduling(t *testing.T) {
+	ctx := context.Background()
+	schedulerConfig, err := RenderKubeSchedulerConfiguration("", 0, 0, api.SchedulerConfigOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := []*unstructured.Unstructured{newNode("n1"), newNode("n2")}
+	for _, n := range nodes {
+		n.SetLabels(map[string]string{"kubernetes.io/hostname": n.GetName()})
+	}
+	pods := []*unstructured.Unstructured{newPod("a", "n1", "500m"), newPod("b", "n2", "500m"), newPod("huge", "", "4")}
+	objs := append(nodes, pods...)
+
+	report, err := simulateScheduling(ctx, schedulerConfig, "", objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumPods != 3 || report.NumScheduled != 2 || report.NumUnschedulable != 1 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if len(report.Unschedulable) != 1 || report.Unschedulable[0].Reason != "Insufficient cpu" {
+		t.Errorf("unexpected unschedulable reasons: %+v", report.Unschedulable)
+	}
+	if report.Placement.After.NumNodesEmpty != 0 {
+		t.Errorf("expected default profile to spread pods: %+v", report.Placement)
+	}
+
+	report, err = simulateScheduling(ctx, schedulerConfig, "bin-packing-scheduler", objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Placement.NumMoved != 1 || report.Placement.After.NumNodesEmpty != 1 {
+		t.Errorf("expected bin-packing profile to pack pods onto one node: %+v", report.Placement)
+	}
+
+	// required pod anti-affinity is enforced by the InterPodAffinity plugin even when bin-packing
+	antiAffinity := map[string]any{"podAntiAffinity": map[string]any{"requiredDuringSchedulingIgnoredDuringExecution": []any{
+		map[string]any{"topologyKey": "kubernetes.io/hostname", "labelSelector": map[string]any{"matchLabels": map[string]any{"app": "x"}}},
+	}}}
+	for _, p := range pods[:2] {
+		p.SetLabels(map[string]string{"app": "x"})
+		p.Object["spec"].(map[string]any)["affinity"] = antiAffinity
+	}
+	report, err = simulateScheduling(ctx, schedulerConfig, "bin-packing-scheduler", objs[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumScheduled != 2 || report.Placement.After.NumNodesEmpty != 0 {
+		t.Errorf("expected anti-affinity to spread pods: %+v", report)
+	}
+
+	tainted := newNode("n1", map[string]any{"key": "dedicated", "value": "x", "effect": "NoSchedule"})
+	report, err = simulateScheduling(ctx, schedulerConfig, "", []*unstructured.Unstructured{tainted, nodes[1], newPod("a", "n1", "500m")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Placement.NumMoved != 1 || report.Placement.Moved[0].To != "n2" {
+		t.Errorf("expected pod to avoid tainted node: %+v", report.Placement)
+	}
+
+	report, err = simulateScheduling(ctx, schedulerConfig, "missing-scheduler", objs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumUnschedulable != 3 || report.Unschedulable[0].Reason != `no profile for scheduler "missing-scheduler"` {
+		t.Errorf("expected pods of unknown scheduler to be unschedulable: %+v", report.Unschedulable)
+	}
+
+	custom := []byte(`apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+  - plugins:
+      score:
+        enabled:
+          - name: Custom
+`)
+	if _, err = simulateScheduling(ctx, custom, "", objs); !errors.Is(err, api.ErrSimulateFailed) {
+		t.Errorf("expected simulate error for unknown plugin, got %v", err)
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"embed"
 	"fmt"
 	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 	"log/slog"
 	"os"
+	"sigs.k8s.io/yaml"
+	"slices"
 	"text/template"
 )
 
@@ -18,41 +22,229 @@ var (
 	kubeSchedulerConfigTemplatePath string = "templates/kube-scheduler-config.yaml"
 )
 
+const defaultSchedulerName = "default-scheduler"
+
 type KubeSchedulerTmplParams struct {
-	KubeConfigPath string
-	QPS            float32
-	Burst          int
+	KubeConfigPath           string
+	QPS                      float32
+	Burst                    int
+	PercentageOfNodesToScore int32
+	Parallelism              int32
 }
 
-func GenKubeSchedulerConfiguration(targetPath string, kubeConfigPath string, poolSize int) error {
-	cfgTmpl, err := getKubeSchedulerConfigTemplate()
+// GenKubeSchedulerConfiguration renders the kube-scheduler configuration for the cluster of the given kubeconfig and
+// writes it to targetPath. See RenderKubeSchedulerConfiguration.
+func GenKubeSchedulerConfiguration(targetPath string, kubeConfigPath string, qps float32, burst int, opts api.SchedulerConfigOpts) error {
+	data, err := RenderKubeSchedulerConfiguration(kubeConfigPath, qps, burst, opts)
 	if err != nil {
 		return err
 	}
-	params := KubeSchedulerTmplParams{
-		KubeConfigPath: kubeConfigPath,
-		QPS:            float32(poolSize),
-		Burst:          poolSize,
+	err = os.WriteFile(targetPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: cannot write %q: %w", api.ErrGenSchedulerConfig, targetPath, err)
 	}
-	err = executeTemplate(cfgTmpl, targetPath, params)
-	return err
+	slog.Info("Wrote kube-scheduler configuration.", "targetPath", targetPath)
+	return nil
 }
 
-func executeTemplate(tmpl *template.Template, targetPath string, params any) error {
+// RenderKubeSchedulerConfiguration renders the kube-scheduler configuration for the cluster of the given kubeconfig
+// with the given client QPS and burst, applies the profile and extender fragments and plugin weights of the given opts
+// and validates the result.
+func RenderKubeSchedulerConfiguration(kubeConfigPath string, qps float32, burst int, opts api.SchedulerConfigOpts) (data []byte, err error) {
+	cfgTmpl, err := getKubeSchedulerConfigTemplate(opts.TemplatePath)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrGenSchedulerConfig, err)
+		return
+	}
+	params := KubeSchedulerTmplParams{
+		KubeConfigPath:           kubeConfigPath,
+		QPS:                      qps,
+		Burst:                    burst,
+		PercentageOfNodesToScore: opts.PercentageOfNodesToScore,
+		Parallelism:              opts.Parallelism,
+	}
 	var output bytes.Buffer
-	err := tmpl.Execute(&output, params)
+	err = cfgTmpl.Execute(&output, params)
 	if err != nil {
-		return fmt.Errorf("%w %q: %w", api.ErrExecTemplate, tmpl.Name(), err)
+		err = fmt.Errorf("%w: %w %q: %w", api.ErrGenSchedulerConfig, api.ErrExecTemplate, cfgTmpl.Name(), err)
+		return
 	}
-	err = os.WriteFile(targetPath, output.Bytes(), 0644)
+	data, err = customizeKubeSchedulerConfiguration(output.Bytes(), opts)
 	if err != nil {
-		return err
+		err = fmt.Errorf("%w: %w", api.ErrGenSchedulerConfig, err)
+	}
+	return
+}
+
+// customizeKubeSchedulerConfiguration applies the fragments and plugin weights of the given opts to the rendered
+// kube-scheduler configuration and validates the result. The configuration is edited as unstructured content so
+// that fields not set by the template or the fragments are not written with zero values.
+func customizeKubeSchedulerConfiguration(rendered []byte, opts api.SchedulerConfigOpts) (data []byte, err error) {
+	var cfg map[string]any
+	if err = yaml.Unmarshal(rendered, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: cannot parse rendered configuration: %w", api.ErrInvalidSchedulerConfig, err)
+	}
+	if opts.PercentageOfNodesToScore != 0 {
+		cfg["percentageOfNodesToScore"] = int64(opts.PercentageOfNodesToScore)
+	}
+	if opts.Parallelism != 0 {
+		cfg["parallelism"] = int64(opts.Parallelism)
+	}
+	profiles, _, _ := unstructured.NestedSlice(cfg, "profiles")
+	for _, p := range opts.ProfilePaths {
+		var profile map[string]any
+		if profile, err = loadSchedulerFragment(p, &configv1.KubeSchedulerProfile{}); err != nil {
+			return
+		}
+		profiles = overlayProfile(profiles, profile)
+	}
+	extenders, _, _ := unstructured.NestedSlice(cfg, "extenders")
+	for _, p := range opts.ExtenderPaths {
+		var extender map[string]any
+		if extender, err = loadSchedulerFragment(p, &configv1.Extender{}); err != nil {
+			return
+		}
+		extenders = append(extenders, extender)
+	}
+	for _, pw := range opts.PluginWeights {
+		if err = setPluginWeight(profiles, pw); err != nil {
+			return
+		}
+	}
+	if len(profiles) > 0 {
+		cfg["profiles"] = profiles
+	}
+	if len(extenders) > 0 {
+		cfg["extenders"] = extenders
+	}
+
+	data, err = yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", api.ErrInvalidSchedulerConfig, err)
+	}
+	if err = validateKubeSchedulerConfiguration(data); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// loadSchedulerFragment loads the YAML fragment at the given path after validating that it strictly decodes into the
+// given typed target.
+func loadSchedulerFragment(path string, target any) (fragment map[string]any, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read fragment %q: %w", api.ErrInvalidSchedulerConfig, path, err)
+	}
+	if err = yaml.UnmarshalStrict(data, target); err != nil {
+		return nil, fmt.Errorf("%w: invalid fragment %q: %w", api.ErrInvalidSchedulerConfig, path, err)
+	}
+	if err = yaml.Unmarshal(data, &fragment); err != nil {
+		return nil, fmt.Errorf("%w: invalid fragment %q: %w", api.ErrInvalidSchedulerConfig, path, err)
+	}
+	return
+}
+
+// overlayProfile replaces the profile of the same scheduler name as the given profile or appends it.
+func overlayProfile(profiles []any, profile map[string]any) []any {
+	name := profileSchedulerName(profile)
+	for i, p := range profiles {
+		if m, ok := p.(map[string]any); ok && profileSchedulerName(m) == name {
+			profiles[i] = profile
+			return profiles
+		}
+	}
+	return append(profiles, profile)
+}
+
+func profileSchedulerName(profile map[string]any) string {
+	name, _, _ := unstructured.NestedString(profile, "schedulerName")
+	if name == "" {
+		return defaultSchedulerName
+	}
+	return name
+}
+
+// setPluginWeight sets the weight of the score plugin in the profile of the given plugin weight, enabling the plugin
+// if necessary.
+func setPluginWeight(profiles []any, pw api.PluginWeight) error {
+	for _, p := range profiles {
+		profile, ok := p.(map[string]any)
+		if !ok || profileSchedulerName(profile) != pw.Profile {
+			continue
+		}
+		enabled, _, _ := unstructured.NestedSlice(profile, "plugins", "score", "enabled")
+		found := false
+		for _, e := range enabled {
+			if plugin, ok := e.(map[string]any); ok && plugin["name"] == pw.Plugin {
+				plugin["weight"] = int64(pw.Weight)
+				found = true
+			}
+		}
+		if !found {
+			enabled = append(enabled, map[string]any{"name": pw.Plugin, "weight": int64(pw.Weight)})
+		}
+		return unstructured.SetNestedSlice(profile, enabled, "plugins", "score", "enabled")
+	}
+	return fmt.Errorf("%w: no profile %q for weight of plugin %q", api.ErrInvalidSchedulerConfig, pw.Profile, pw.Plugin)
+}
+
+// validateKubeSchedulerConfiguration strictly decodes the given data into a KubeSchedulerConfiguration and checks
+// the constraints which would prevent kube-scheduler from starting.
+func validateKubeSchedulerConfiguration(data []byte) error {
+	var cfg configv1.KubeSchedulerConfiguration
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return fmt.Errorf("%w: %w", api.ErrInvalidSchedulerConfig, err)
+	}
+	if cfg.APIVersion != configv1.SchemeGroupVersion.String() || cfg.Kind != "KubeSchedulerConfiguration" {
+		return fmt.Errorf("%w: unexpected apiVersion %q and kind %q", api.ErrInvalidSchedulerConfig, cfg.APIVersion, cfg.Kind)
+	}
+	if cfg.Parallelism != nil && *cfg.Parallelism <= 0 {
+		return fmt.Errorf("%w: parallelism must be positive", api.ErrInvalidSchedulerConfig)
+	}
+	if cfg.PercentageOfNodesToScore != nil && (*cfg.PercentageOfNodesToScore < 0 || *cfg.PercentageOfNodesToScore > 100) {
+		return fmt.Errorf("%w: percentageOfNodesToScore must be in [0, 100]", api.ErrInvalidSchedulerConfig)
+	}
+	names := make(map[string]struct{}, len(cfg.Profiles))
+	for _, p := range cfg.Profiles {
+		name := defaultSchedulerName
+		if p.SchedulerName != nil && *p.SchedulerName != "" {
+			name = *p.SchedulerName
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("%w: duplicate profile %q", api.ErrInvalidSchedulerConfig, name)
+		}
+		names[name] = struct{}{}
+		if p.Plugins == nil {
+			continue
+		}
+		for _, plugin := range slices.Concat(p.Plugins.Score.Enabled, p.Plugins.MultiPoint.Enabled) {
+			if plugin.Weight != nil && *plugin.Weight <= 0 {
+				return fmt.Errorf("%w: weight of plugin %q in profile %q must be positive", api.ErrInvalidSchedulerConfig, plugin.Name, name)
+			}
+		}
+	}
+	for _, e := range cfg.Extenders {
+		if e.URLPrefix == "" {
+			return fmt.Errorf("%w: extender without urlPrefix", api.ErrInvalidSchedulerConfig)
+		}
 	}
-	slog.Info("Executed template and wrote target.", "templateName", tmpl.Name(), "targetPath", targetPath)
 	return nil
 }
 
-func getKubeSchedulerConfigTemplate() (configTemplate *template.Template, err error) {
+func getKubeSchedulerConfigTemplate(templatePath string) (configTemplate *template.Template, err error) {
+	if templatePath != "" {
+		var data []byte
+		data, err = os.ReadFile(templatePath)
+		if err != nil {
+			err = fmt.Errorf("%w: cannot read kube-scheduler config template %q: %w", api.ErrLoadTemplate, templatePath, err)
+			return
+		}
+		configTemplate, err = template.New(templatePath).Parse(string(data))
+		if err != nil {
+			err = fmt.Errorf("%w: cannot parse kube-scheduler config template %q: %w", api.ErrLoadTemplate, templatePath, err)
+		}
+		return
+	}
 	if kubeSchedulerConfigTemplate != nil {
 		configTemplate = kubeSchedulerConfigTemplate
 		return
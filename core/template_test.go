@@ -0,0 +1,70 @@
+package core
+
+import (
+	"errors"
+	"github.com/elankath/kcpcl/api"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"testing"
+)
+
+func TestGenKubeSchedulerConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	profilePath := filepath.Join(dir, "profile.yaml")
+	profile := `schedulerName: bin-packing-scheduler
+plugins:
+  score:
+    enabled:
+      - name: NodeResourcesFit
+        weight: 2
+`
+	if err := os.WriteFile(profilePath, []byte(profile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	targetPath := filepath.Join(dir, "kube-scheduler-config.yaml")
+	opts := api.SchedulerConfigOpts{
+		ProfilePaths:             []string{profilePath},
+		PercentageOfNodesToScore: 50,
+		PluginWeights:            []api.PluginWeight{{Profile: "default-scheduler", Plugin: "ImageLocality", Weight: 3}},
+	}
+	if err := GenKubeSchedulerConfiguration(targetPath, "/tmp/kubeconfig.yaml", 15, 10, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg configv1.KubeSchedulerConfiguration
+	if err = yaml.UnmarshalStrict(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ClientConnection.QPS != 15 || cfg.ClientConnection.Burst != 10 {
+		t.Errorf("expected client QPS 15 and burst 10, got %+v", cfg.ClientConnection)
+	}
+	if cfg.PercentageOfNodesToScore == nil || *cfg.PercentageOfNodesToScore != 50 {
+		t.Errorf("expected percentageOfNodesToScore 50, got %v", cfg.PercentageOfNodesToScore)
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(cfg.Profiles))
+	}
+	if enabled := cfg.Profiles[0].Plugins.Score.Enabled; len(enabled) != 1 || enabled[0].Name != "ImageLocality" || *enabled[0].Weight != 3 {
+		t.Errorf("unexpected score plugins of default profile: %+v", enabled)
+	}
+	if plugins := cfg.Profiles[1].Plugins; plugins.Score.Disabled != nil || *plugins.Score.Enabled[0].Weight != 2 {
+		t.Errorf("expected bin-packing profile to be replaced by fragment: %+v", plugins)
+	}
+
+	opts = api.SchedulerConfigOpts{PluginWeights: []api.PluginWeight{{Profile: "missing", Plugin: "ImageLocality", Weight: 1}}}
+	if err = GenKubeSchedulerConfiguration(targetPath, "/tmp/kubeconfig.yaml", 15, 10, opts); !errors.Is(err, api.ErrInvalidSchedulerConfig) {
+		t.Errorf("expected invalid scheduler config error for unknown profile, got %v", err)
+	}
+	if err = os.WriteFile(profilePath, []byte("schedulerName: x\nunknownField: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts = api.SchedulerConfigOpts{ProfilePaths: []string{profilePath}}
+	if err = GenKubeSchedulerConfiguration(targetPath, "/tmp/kubeconfig.yaml", 15, 10, opts); !errors.Is(err, api.ErrInvalidSchedulerConfig) {
+		t.Errorf("expected invalid scheduler config error for unknown field, got %v", err)
+	}
+}
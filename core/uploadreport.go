@@ -0,0 +1,132 @@
+package core
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// uploadRecorder collects the outcome of every object uploaded by the KindUploaders sharing it.
+type uploadRecorder struct {
+	mu        sync.Mutex
+	results   []api.ObjUploadResult
+	numFailed int
+}
+
+// record records the outcome of uploading obj of the given resource along with its retries and error.
+func (r *uploadRecorder) record(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, outcome api.UploadOutcome, retries int, err error, latency time.Duration) {
+	result := api.ObjUploadResult{
+		ID:      api.ObjID{GVR: api.FormatGVR(gvr), Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		Kind:    obj.GetKind(),
+		Outcome: outcome,
+		Latency: latency,
+		Retries: retries,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+	if result.Outcome == api.UploadFailed {
+		r.numFailed++
+	}
+}
+
+func (r *uploadRecorder) failures() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.numFailed
+}
+
+func (r *uploadRecorder) report() api.UploadReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return newUploadReport(slices.Clone(r.results))
+}
+
+func newUploadReport(results []api.ObjUploadResult) (report api.UploadReport) {
+	slices.SortFunc(results, func(a, b api.ObjUploadResult) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.ID.Namespace, b.ID.Namespace), cmp.Compare(a.ID.Name, b.ID.Name))
+	})
+	report.NumObjs = len(results)
+	report.Objects = results
+	for i := 0; i < len(results); {
+		summary := api.KindUploadSummary{Kind: results[i].Kind}
+		var latencies []time.Duration
+		for ; i < len(results) && results[i].Kind == summary.Kind; i++ {
+			switch results[i].Outcome {
+			case api.UploadCreated:
+				summary.NumCreated++
+			case api.UploadUpdated:
+				summary.NumUpdated++
+			case api.UploadReplaced:
+				summary.NumReplaced++
+			case api.UploadSkippedExists:
+				summary.NumSkippedExists++
+			case api.UploadForbidden:
+				summary.NumForbidden++
+			case api.UploadFailed:
+				summary.NumFailed++
+			}
+			summary.NumRetries += results[i].Retries
+			latencies = append(latencies, results[i].Latency)
+		}
+		summary.Latency = durationPercentiles(latencies)
+		report.NumCreated += summary.NumCreated
+		report.NumUpdated += summary.NumUpdated
+		report.NumReplaced += summary.NumReplaced
+		report.NumSkippedExists += summary.NumSkippedExists
+		report.NumForbidden += summary.NumForbidden
+		report.NumFailed += summary.NumFailed
+		report.NumRetries += summary.NumRetries
+		report.Kinds = append(report.Kinds, summary)
+	}
+	return
+}
+
+// saveUploadReport writes the given upload report as JSON to path.
+func saveUploadReport(path string, report api.UploadReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: cannot marshal upload report: %w", api.ErrUploadFailed, err)
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: cannot write upload report to %q: %w", api.ErrUploadFailed, path, err)
+	}
+	return nil
+}
+
+// WriteUploadReport writes the given upload report to w in the given format. The text format only holds the summary
+// table per kind.
+func WriteUploadReport(w io.Writer, report api.UploadReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "KIND\tCREATED\tUPDATED\tREPLACED\tSKIPPED-EXISTS\tFORBIDDEN\tFAILED\tRETRIES\tLATENCY P50\tLATENCY MAX")
+		for _, k := range report.Kinds {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n", k.Kind, k.NumCreated, k.NumUpdated, k.NumReplaced, k.NumSkippedExists,
+				k.NumForbidden, k.NumFailed, k.NumRetries, k.Latency.P50, k.Latency.Max)
+		}
+		_, _ = fmt.Fprintf(tw, "TOTAL\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t\t\n", report.NumCreated, report.NumUpdated, report.NumReplaced, report.NumSkippedExists,
+			report.NumForbidden, report.NumFailed, report.NumRetries)
+		err := tw.Flush()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Duration: %s, throughput: %.1f objects/s\n", report.Duration.Round(time.Millisecond), report.Throughput)
+		if c := report.Concurrency; c != nil {
+			_, _ = fmt.Fprintf(w, "Concurrency: min=%d max=%d final=%d decreases=%d\n", c.Min, c.Max, c.Final, c.NumDecreases)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKindUploaderPolicy(t *testing.T) {
+	ctx := context.Background()
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR:    "PodList",
+		secretsGVR: "SecretList",
+	}, newObj("Pod", "default", "existing"), newObj("Secret", "default", "existing"))
+	client.PrependReactor("create", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		obj := action.(clienttesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if obj.GetName() == "denied" {
+			return true, nil, errors.NewForbidden(secretsGVR.GroupResource(), obj.GetName(), nil)
+		}
+		return false, nil, nil
+	})
+	client.PrependReactor("patch", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if name := action.(clienttesting.PatchAction).GetName(); name == "existing" {
+			return true, nil, errors.NewForbidden(podsGVR.GroupResource(), name, nil)
+		}
+		return false, nil, nil
+	})
+	recorder := &uploadRecorder{}
+	newUploader := func(kind string, policy api.UploadPolicy) *KindUploader {
+		gvr := podsGVR
+		if kind == "Secret" {
+			gvr = secretsGVR
+		}
+		return &KindUploader{GVK: schema.GroupVersionKind{Version: "v1", Kind: kind}, GVR: gvr, ResourceFacade: client.Resource(gvr), Counter: &atomic.Uint32{}, Policy: policy, recorder: recorder}
+	}
+	policy, err := api.ParseUploadPolicy([]string{"fail", "Secret=replace"}, []string{"Secret=fail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods, secrets := newUploader("Pod", policy), newUploader("Secret", policy)
+	if err = pods.Upload(ctx, newObj("Pod", "default", "new")); err != nil {
+		t.Errorf("expected new pod to be created, got %v", err)
+	}
+	if err = pods.Upload(ctx, newObj("Pod", "default", "existing")); err == nil {
+		t.Errorf("expected existing pod to fail with fail policy")
+	}
+	if err = secrets.Upload(ctx, newObj("Secret", "default", "existing")); err != nil {
+		t.Errorf("expected existing secret to be replaced, got %v", err)
+	}
+	if err = secrets.Upload(ctx, newObj("Secret", "default", "denied")); err == nil {
+		t.Errorf("expected forbidden secret to fail with fail policy")
+	}
+	if err = newUploader("Secret", api.UploadPolicy{}).Upload(ctx, newObj("Secret", "default", "denied")); err != nil {
+		t.Errorf("expected forbidden secret to be skipped by default, got %v", err)
+	}
+	updatePolicy, err := api.ParseUploadPolicy([]string{"update"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = newUploader("Pod", updatePolicy).Upload(ctx, newObj("Pod", "default", "existing")); err != nil {
+		t.Errorf("expected forbidden update of existing pod to be skipped by default, got %v", err)
+	}
+	updatePolicy.OnForbidden = api.ForbiddenFail
+	if err = newUploader("Pod", updatePolicy).Upload(ctx, newObj("Pod", "default", "existing")); !errors.IsForbidden(err) {
+		t.Errorf("expected forbidden update of existing pod to fail with fail policy, got %v", err)
+	}
+	if recorder.failures() != 3 {
+		t.Errorf("expected 3 failures, got %d", recorder.failures())
+	}
+
+	report := recorder.report()
+	if report.NumObjs != 7 || report.NumCreated != 1 || report.NumReplaced != 1 || report.NumForbidden != 2 || report.NumFailed != 3 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if len(report.Kinds) != 2 || report.Kinds[0].Kind != "Pod" || report.Kinds[0].NumForbidden != 1 || report.Kinds[1].NumFailed != 1 {
+		t.Errorf("unexpected kind summaries: %+v", report.Kinds)
+	}
+	if _, err = api.ParseUploadPolicy([]string{"Secret=overwrite"}, nil); err == nil {
+		t.Errorf("expected invalid policy error")
+	}
+}
+
+func TestUploadRecorder(t *testing.T) {
+	secretsGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	recorder := &uploadRecorder{}
+	recorder.record(secretsGVR, newObj("Secret", "default", "s2"), api.UploadSkippedExists, 0, nil, 3*time.Millisecond)
+	recorder.record(podsGVR, newObj("Pod", "default", "p2"), api.UploadCreated, 2, nil, 40*time.Millisecond)
+	recorder.record(podsGVR, newObj("Pod", "default", "p1"), api.UploadCreated, 0, nil, 10*time.Millisecond)
+	recorder.record(secretsGVR, newObj("Secret", "default", "s1"), api.UploadUpdated, 0, nil, 5*time.Millisecond)
+	recorder.record(podsGVR, newObj("Pod", "default", "p3"), api.UploadFailed, 1, fmt.Errorf("boom"), 20*time.Millisecond)
+
+	g := &GardenerShootCopier{cfg: api.CopierConfig{MaxUploadFailures: 1}}
+	if err := g.checkUploadFailures(recorder); err != nil {
+		t.Errorf("expected 1 failure to be within the threshold, got %v", err)
+	}
+	recorder.record(secretsGVR, newObj("Secret", "default", "s3"), api.UploadFailed, 0, fmt.Errorf("boom"), time.Millisecond)
+	if err := g.checkUploadFailures(recorder); err == nil {
+		t.Errorf("expected 2 failures to exceed the threshold of 1")
+	}
+
+	report := recorder.report()
+	if report.NumObjs != 6 || report.NumCreated != 2 || report.NumUpdated != 1 || report.NumSkippedExists != 1 || report.NumFailed != 2 || report.NumRetries != 3 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if len(report.Kinds) != 2 {
+		t.Fatalf("expected 2 kind summaries, got %+v", report.Kinds)
+	}
+	if pods := report.Kinds[0]; pods.Kind != "Pod" || pods.NumCreated != 2 || pods.NumFailed != 1 || pods.NumRetries != 3 ||
+		pods.Latency.P50 != 20*time.Millisecond || pods.Latency.Max != 40*time.Millisecond {
+		t.Errorf("unexpected pod summary: %+v", pods)
+	}
+	if ids := []string{report.Objects[0].ID.Name, report.Objects[1].ID.Name, report.Objects[2].ID.Name}; !slices.Equal(ids, []string{"p1", "p2", "p3"}) {
+		t.Errorf("expected objects sorted by kind and name, got %v", ids)
+	}
+	if failed := report.Objects[2]; failed.Outcome != api.UploadFailed || failed.Error != "boom" || failed.ID.GVR != "v1/pods" {
+		t.Errorf("unexpected failed object result: %+v", failed)
+	}
+
+	path := filepath.Join(t.TempDir(), "upload-report.json")
+	if err := saveUploadReport(path, report); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved api.UploadReport
+	if err = json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.NumObjs != report.NumObjs || len(saved.Objects) != len(report.Objects) {
+		t.Errorf("unexpected saved report: %+v", saved)
+	}
+
+	var out bytes.Buffer
+	if err = WriteUploadReport(&out, report, api.OutputTable); err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Join(strings.Fields(out.String()), " ")
+	for _, row := range []string{"Pod 2 0 0 0 0 1 3 20ms 40ms", "Secret 0 1 0 1 0 1 0 3ms 5ms", "TOTAL 2 1 0 1 0 2 3"} {
+		if !strings.Contains(text, row) {
+			t.Errorf("expected row %q in upload report:\n%s", row, out.String())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ValidateObjDir validates the objects in the given obj dir. It reports files that cannot be parsed, files whose name
+// or resource dir does not match the object they hold and references to objects that are missing from the obj dir.
+// Missing references that do not prevent upload or scheduling, such as optional or owner references and
+// configmaps/secrets, are reported as warnings.
+func ValidateObjDir(baseObjDir string) (report api.ValidationReport, err error) {
+	type loadedObj struct {
+		path string
+		obj  *unstructured.Unstructured
+	}
+	var loaded []loadedObj
+	index := make(map[string]struct{})
+	addProblem := func(path string, severity api.Severity, format string, args ...any) {
+		report.Problems = append(report.Problems, api.ValidationProblem{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	err = walkObjFiles(baseObjDir, func(path string) error {
+		gvr, err := parseResourceDirName(filepath.Base(filepath.Dir(path)))
+		if err != nil {
+			addProblem(path, api.SeverityError, "%v", err)
+			return nil
+		}
+		obj, err := LoadObj(path)
+		if err != nil {
+			addProblem(path, api.SeverityError, "%v", err)
+			return nil
+		}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			addProblem(path, api.SeverityError, "object has no kind or name")
+			return nil
+		}
+		report.NumObjs++
+		if expected := objFileName(obj.GetNamespace(), obj.GetName()); expected != filepath.Base(path) {
+			addProblem(path, api.SeverityError, "file name does not match object %s, expected %q", objDesc(obj), expected)
+		}
+		if gv := obj.GroupVersionKind().GroupVersion(); gv != gvr.GroupVersion() {
+			addProblem(path, api.SeverityError, "apiVersion %q of object %s does not match resource dir %q", gv, objDesc(obj), resourceDirName(gvr))
+		}
+		index[closureKey(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())] = struct{}{}
+		loaded = append(loaded, loadedObj{path: path, obj: obj})
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("%w: %w", api.ErrValidateFailed, err)
+		return
+	}
+
+	for _, lo := range loaded {
+		refs, err := objRefs(lo.obj)
+		if err != nil {
+			addProblem(lo.path, api.SeverityError, "%v", err)
+			continue
+		}
+		for _, r := range refs {
+			if r.Name == "" {
+				continue
+			}
+			if _, ok := index[closureKey(r.GVK, r.Namespace, r.Name)]; ok {
+				continue
+			}
+			severity := api.SeverityError
+			if r.Optional || r.GVK.GroupKind() == configMapGVK.GroupKind() || r.GVK.GroupKind() == secretGVK.GroupKind() {
+				severity = api.SeverityWarning
+			}
+			addProblem(lo.path, severity, "%s references missing %s via %s", objDesc(lo.obj), r, r.Field)
+		}
+	}
+
+	slices.SortFunc(report.Problems, func(a, b api.ValidationProblem) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Message, b.Message))
+	})
+	for _, p := range report.Problems {
+		if p.Severity == api.SeverityError {
+			report.NumErrors++
+		} else {
+			report.NumWarnings++
+		}
+	}
+	return
+}
+
+// WriteValidationReport writes the given validation report to w in the given format.
+func WriteValidationReport(w io.Writer, report api.ValidationReport, format api.OutputFormat) error {
+	return writeReport(w, format, report, func(w io.Writer) error {
+		for _, p := range report.Problems {
+			_, _ = fmt.Fprintf(w, "%s: %s: %s\n", strings.ToUpper(string(p.Severity)), p.Path, p.Message)
+		}
+		_, err := fmt.Fprintf(w, "Summary: objects=%d errors=%d warnings=%d\n", report.NumObjs, report.NumErrors, report.NumWarnings)
+		return err
+	})
+}
+
+func objDesc(obj *unstructured.Unstructured) string {
+	return objRef{GVK: obj.GroupVersionKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+}
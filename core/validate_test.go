@@ -0,0 +1,63 @@
+package core
+
+import (
+	"github.com/elankath/kcpcl/api"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateObjDir(t *testing.T) {
+	baseObjDir := t.TempDir()
+	namespacesGVR := namespacesGR.WithVersion("v1")
+	configMapsGVR := configMapGVK.GroupVersion().WithResource("configmaps")
+	writeObj(t, baseObjDir, namespacesGVR, newObj("Namespace", "", "default"))
+	writeObj(t, baseObjDir, configMapsGVR.GroupVersion().WithResource("serviceaccounts"), newObj("ServiceAccount", "default", "default"))
+	pod := newPod("p", "", "")
+	pod.Object["spec"].(map[string]any)["volumes"] = []any{
+		map[string]any{"name": "data", "persistentVolumeClaim": map[string]any{"claimName": "data"}},
+		map[string]any{"name": "cfg", "configMap": map[string]any{"name": "cfg"}},
+	}
+	writeObj(t, baseObjDir, podsGVR, pod)
+	// file name of a config map that does not match its name
+	misnamed := filepath.Join(baseObjDir, resourceDirName(configMapsGVR), objFileName("default", "other"))
+	if err := os.MkdirAll(filepath.Dir(misnamed), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeObjToYAMLFile(misnamed, newObj("ConfigMap", "default", "cm")); err != nil {
+		t.Fatal(err)
+	}
+	writeObj(t, baseObjDir, configMapsGVR, newObj("ConfigMap", "default", "ok"))
+	// core/v1 object in the resource dir of an apps/v1 resource
+	writeObj(t, baseObjDir, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, newObj("Deployment", "default", "d"))
+
+	report, err := ValidateObjDir(baseObjDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NumObjs != 6 || report.NumErrors != 3 || report.NumWarnings != 1 {
+		t.Errorf("unexpected counts: objs=%d errors=%d warnings=%d problems=%+v", report.NumObjs, report.NumErrors, report.NumWarnings, report.Problems)
+	}
+	for _, expected := range []struct {
+		file     string
+		severity api.Severity
+		message  string
+	}{
+		{"default@d.yaml", api.SeverityError, `does not match resource dir "apps-v1-deployments"`},
+		{"default@other.yaml", api.SeverityError, `expected "default@cm.yaml"`},
+		{"default@p.yaml", api.SeverityError, "references missing PersistentVolumeClaim default/data"},
+		{"default@p.yaml", api.SeverityWarning, "references missing ConfigMap default/cfg"},
+	} {
+		found := false
+		for _, p := range report.Problems {
+			if filepath.Base(p.Path) == expected.file && p.Severity == expected.severity && strings.Contains(p.Message, expected.message) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s problem for %s containing %q, got %+v", expected.severity, expected.file, expected.message, report.Problems)
+		}
+	}
+}
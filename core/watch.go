@@ -0,0 +1,241 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elankath/kcpcl/api"
+	"io/fs"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var EventLogFilename = "events.jsonl"
+
+func (g *GardenerShootCopier) WatchObjects(ctx context.Context, baseObjDir string, gvrList []schema.GroupVersionResource) (err error) {
+	err = g.DownloadObjects(ctx, baseObjDir, gvrList)
+	if err != nil {
+		return fmt.Errorf("%w: initial download failed: %w", api.ErrWatchFailed, err)
+	}
+
+	var recorder *eventRecorder
+	if g.cfg.RecordEvents {
+		recorder, err = newEventRecorder(filepath.Join(baseObjDir, EventLogFilename))
+		if err != nil {
+			return fmt.Errorf("%w: %w", api.ErrWatchFailed, err)
+		}
+		defer func() {
+			_ = recorder.Close()
+		}()
+	}
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(g.dynamicClient, 0)
+	informers := make(map[schema.GroupVersionResource]cache.SharedIndexInformer, len(gvrList))
+	for _, gvr := range gvrList {
+		syncer := &objDirSyncer{
+			gvr:               gvr,
+			resourceDir:       filepath.Join(baseObjDir, resourceDirName(gvr)),
+			includeSecretData: g.cfg.IncludeSecretData,
+			recorder:          recorder,
+		}
+		informer := factory.ForResource(gvr).Informer()
+		_, err = informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc:    syncer.OnAdd,
+			UpdateFunc: syncer.OnUpdate,
+			DeleteFunc: syncer.OnDelete,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: cannot add event handler for gvr %q: %w", api.ErrWatchFailed, gvr, err)
+		}
+		informers[gvr] = informer
+	}
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	for gvr, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%w: cannot sync informer cache for gvr %q", api.ErrWatchFailed, gvr)
+		}
+	}
+	for gvr, informer := range informers {
+		err = pruneResourceDir(filepath.Join(baseObjDir, resourceDirName(gvr)), informer.GetStore())
+		if err != nil {
+			return fmt.Errorf("%w: %w", api.ErrWatchFailed, err)
+		}
+	}
+	slog.Info("Watching objects. Cancel to stop.", "baseObjDir", baseObjDir, "gvrs", gvrList, "recordEvents", g.cfg.RecordEvents)
+	<-ctx.Done()
+	slog.Info("Stopped watching objects.", "baseObjDir", baseObjDir)
+	return nil
+}
+
+// objDirSyncer is an event handler that keeps the resource dir of a GVR in sync with the watched objects.
+type objDirSyncer struct {
+	gvr               schema.GroupVersionResource
+	resourceDir       string
+	includeSecretData bool
+	recorder          *eventRecorder
+}
+
+func (s *objDirSyncer) OnAdd(obj any, isInInitialList bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	u = s.write(u)
+	if u == nil || isInInitialList {
+		return
+	}
+	s.record(watch.Added, u)
+}
+
+func (s *objDirSyncer) OnUpdate(_, newObj any) {
+	u, ok := newObj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	u = s.write(u)
+	if u == nil {
+		return
+	}
+	s.record(watch.Modified, u)
+}
+
+func (s *objDirSyncer) OnDelete(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	filename := filepath.Join(s.resourceDir, objFileName(u.GetNamespace(), u.GetName()))
+	err := os.Remove(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Error("Cannot remove deleted object file.", "filename", filename, "error", err)
+	} else if err == nil {
+		slog.Info("Removed deleted object.", "filename", filename)
+	}
+	u = s.redact(u)
+	if u == nil {
+		return
+	}
+	s.record(watch.Deleted, u)
+}
+
+// write saves a copy of obj to the resource dir and returns the copy that was written or nil if writing failed.
+func (s *objDirSyncer) write(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = s.redact(obj)
+	if obj == nil {
+		return nil
+	}
+	filename := filepath.Join(s.resourceDir, objFileName(obj.GetNamespace(), obj.GetName()))
+	err := writeObjToYAMLFile(filename, obj)
+	if err != nil {
+		slog.Error("Cannot write watched object.", "filename", filename, "error", err)
+		return nil
+	}
+	slog.Debug("Wrote watched object.", "filename", filename)
+	return obj
+}
+
+// redact returns a copy of obj with redacted secret data unless secret data is included or nil if redacting failed.
+func (s *objDirSyncer) redact(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	if s.includeSecretData {
+		return obj
+	}
+	err := RedactSecretData(obj)
+	if err != nil {
+		slog.Error("Cannot redact secret data.", "name", obj.GetName(), "namespace", obj.GetNamespace(), "error", err)
+		return nil
+	}
+	return obj
+}
+
+func (s *objDirSyncer) record(eventType watch.EventType, obj *unstructured.Unstructured) {
+	if s.recorder == nil {
+		return
+	}
+	err := s.recorder.Record(api.ObjEvent{
+		Time:   time.Now().UTC(),
+		Type:   eventType,
+		GVR:    api.FormatGVR(s.gvr),
+		Object: obj,
+	})
+	if err != nil {
+		slog.Error("Cannot record object event.", "type", eventType, "name", obj.GetName(), "namespace", obj.GetNamespace(), "error", err)
+	}
+}
+
+// pruneResourceDir removes object files from resourceDir for objects no longer present in the given store.
+func pruneResourceDir(resourceDir string, store cache.Store) error {
+	expected := make(map[string]struct{})
+	for _, obj := range store.List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		expected[objFileName(u.GetNamespace(), u.GetName())] = struct{}{}
+	}
+	entries, err := os.ReadDir(resourceDir)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read %q: %w", api.ErrCantReadObjDir, resourceDir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+		if _, ok := expected[e.Name()]; ok {
+			continue
+		}
+		filename := filepath.Join(resourceDir, e.Name())
+		err = os.Remove(filename)
+		if err != nil {
+			return fmt.Errorf("cannot remove stale object file %q: %w", filename, err)
+		}
+		slog.Info("Removed stale object.", "filename", filename)
+	}
+	return nil
+}
+
+// eventRecorder appends object events as JSON lines to an event log.
+type eventRecorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+func newEventRecorder(path string) (*eventRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot open event log %q: %w", api.ErrSaveObj, path, err)
+	}
+	slog.Info("Recording object events.", "path", path)
+	return &eventRecorder{file: f, encoder: json.NewEncoder(f)}, nil
+}
+
+func (r *eventRecorder) Record(event api.ObjEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.encoder.Encode(event)
+}
+
+func (r *eventRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestObjDirSyncer(t *testing.T) {
+	baseObjDir := t.TempDir()
+	secretsGVR := corev1.SchemeGroupVersion.WithResource("secrets")
+	resourceDir := filepath.Join(baseObjDir, resourceDirName(secretsGVR))
+	if err := os.MkdirAll(resourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	eventLogPath := filepath.Join(baseObjDir, EventLogFilename)
+	recorder, err := newEventRecorder(eventLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncer := &objDirSyncer{gvr: secretsGVR, resourceDir: resourceDir, recorder: recorder}
+
+	secret := newObj("Secret", "default", "creds")
+	secret.Object["data"] = map[string]any{"password": base64.StdEncoding.EncodeToString([]byte("secret"))}
+	filename := filepath.Join(resourceDir, objFileName("default", "creds"))
+
+	syncer.OnAdd(newObj("Secret", "default", "initial"), true)
+	syncer.OnAdd(secret, false)
+	if data, _, _ := unstructured.NestedStringMap(secret.Object, "data"); data["password"] != base64.StdEncoding.EncodeToString([]byte("secret")) {
+		t.Errorf("expected the informer object to be left unchanged, got %v", data)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), base64.StdEncoding.EncodeToString([]byte("secret"))) {
+		t.Errorf("expected secret data to be redacted in %q:\n%s", filename, content)
+	}
+
+	updated := secret.DeepCopy()
+	updated.SetLabels(map[string]string{"rotated": "true"})
+	syncer.OnUpdate(secret, updated)
+	syncer.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/creds", Obj: updated})
+	if _, err = os.Stat(filename); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %q to be removed on delete, got %v", filename, err)
+	}
+	if err = recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := LoadObjEvents(eventLogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[0].Type != watch.Added || events[1].Type != watch.Modified || events[2].Type != watch.Deleted {
+		t.Fatalf("expected added, modified and deleted events without the initial list, got %v", events)
+	}
+	for _, e := range events {
+		data, _, _ := unstructured.NestedStringMap(e.Object.Object, "data")
+		if decoded, _ := base64.StdEncoding.DecodeString(data["password"]); string(decoded) != "******" {
+			t.Errorf("expected secret data to be redacted in %s event, got %q", e.Type, decoded)
+		}
+	}
+}
+
+func TestPruneResourceDir(t *testing.T) {
+	baseObjDir := t.TempDir()
+	configMapsGVR := corev1.SchemeGroupVersion.WithResource("configmaps")
+	live, stale := newObj("ConfigMap", "default", "live"), newObj("ConfigMap", "default", "stale")
+	writeObj(t, baseObjDir, configMapsGVR, live)
+	writeObj(t, baseObjDir, configMapsGVR, stale)
+	resourceDir := filepath.Join(baseObjDir, resourceDirName(configMapsGVR))
+	notes := filepath.Join(resourceDir, "notes.txt")
+	if err := os.WriteFile(notes, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if err := store.Add(live); err != nil {
+		t.Fatal(err)
+	}
+	if err := pruneResourceDir(resourceDir, store); err != nil {
+		t.Fatal(err)
+	}
+	for filename, wantExists := range map[string]bool{
+		filepath.Join(resourceDir, objFileName("default", "live")):  true,
+		filepath.Join(resourceDir, objFileName("default", "stale")): false,
+		notes: true,
+	} {
+		if _, err := os.Stat(filename); (err == nil) != wantExists {
+			t.Errorf("expected %q to exist: %t, got %v", filename, wantExists, err)
+		}
+	}
+}
@@ -7,26 +7,68 @@ import (
 	"github.com/elankath/kcpcl/api"
 	"github.com/elankath/kcpcl/cli"
 	"github.com/elankath/kcpcl/core"
+	"github.com/elankath/kcpcl/core/clientutil"
 	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
 	"log/slog"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"strings"
+	"syscall"
 )
 
+// subCommands holds the name and arguments of every sub-command for printing usage.
+var subCommands = []struct{ name, args string }{
+	{"upload", "<flags> <args>"},
+	{"download", "<flags> <args>"},
+	{"watch", "<flags> <args>"},
+	{"replay", "<flags>"},
+	{"copy", "<flags> <args>"},
+	{"copyshoot", "<flags> <args>"},
+	{"diff", "<flags> <args>"},
+	{"inspect", "<flags>"},
+	{"report placement", "<flags>"},
+	{"simulate", "<flags>"},
+	{"validate", "<flags>"},
+	{"genkubeconfig", "<flags>"},
+	{"gen-scheduler-config", "<flags>"},
+}
+
+// failureErrs holds the errors of sub-commands that failed after their options were validated. The usage of the
+// sub-command is not printed for them.
+var failureErrs = []error{
+	api.ErrUploadFailed,
+	api.ErrDownloadFailed,
+	api.ErrWatchFailed,
+	api.ErrReplayFailed,
+	api.ErrCopyFailed,
+	api.ErrDiffFailed,
+	api.ErrInspectFailed,
+	api.ErrReportFailed,
+	api.ErrValidateFailed,
+	api.ErrGenKubeConfig,
+	api.ErrWaitScheduled,
+	api.ErrGenSchedulerConfig,
+	api.ErrSimulateFailed,
+	api.ErrGenKWOKConfig,
+	api.ErrSimulateLifecycle,
+}
+
 func main() {
 	var err error
 	var exitCode int
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	info, ok := debug.ReadBuildInfo()
 	if ok {
 		if info.Main.Version != "" {
-			fmt.Printf("%s version: %s\n", api.ProgramName, info.Main.Version)
+			_, _ = fmt.Fprintf(os.Stderr, "%s version: %s\n", api.ProgramName, info.Main.Version)
 		}
 	} else {
-		fmt.Printf("%s: binary build info not embedded", api.ProgramName)
+		_, _ = fmt.Fprintf(os.Stderr, "%s: binary build info not embedded", api.ProgramName)
 	}
 
 	if len(os.Args) < 2 {
@@ -40,11 +82,33 @@ func main() {
 		exitCode, err = ExecDownload(ctx, subCommandFlags, os.Args[2:])
 	case "upload":
 		exitCode, err = ExecUpload(ctx, subCommandFlags, os.Args[2:])
+	case "watch":
+		exitCode, err = ExecWatch(ctx, subCommandFlags, os.Args[2:])
+	case "replay":
+		exitCode, err = ExecReplay(ctx, subCommandFlags, os.Args[2:])
+	case "copy":
+		exitCode, err = ExecCopy(ctx, subCommandFlags, os.Args[2:])
+	case "copyshoot":
+		exitCode, err = ExecCopyShoot(ctx, subCommandFlags, os.Args[2:])
+	case "diff":
+		exitCode, err = ExecDiff(ctx, subCommandFlags, os.Args[2:])
+	case "inspect":
+		exitCode, err = ExecInspect(subCommandFlags, os.Args[2:])
+	case "report":
+		exitCode, err = ExecReport(ctx, subCommandFlags, os.Args[2:])
+	case "simulate":
+		exitCode, err = ExecSimulate(subCommandFlags, os.Args[2:])
+	case "validate":
+		exitCode, err = ExecValidate(subCommandFlags, os.Args[2:])
+	case "genkubeconfig":
+		exitCode, err = ExecGenKubeConfig(ctx, subCommandFlags, os.Args[2:])
+	case "gen-scheduler-config":
+		exitCode, err = ExecGenSchedulerConfig(subCommandFlags, os.Args[2:])
 	case "help", "-h", "--help":
-		_, _ = fmt.Fprintf(os.Stderr, `Please invoke one of the below:
-		%s download -h  
-		%s upload -h
-`, api.ProgramName, api.ProgramName)
+		_, _ = fmt.Fprintln(os.Stderr, "Please invoke one of the below:")
+		for _, sc := range subCommands {
+			_, _ = fmt.Fprintf(os.Stderr, "\t%s %s -h\n", api.ProgramName, sc.name)
+		}
 	default:
 		printExpectedSubCommand()
 		os.Exit(cli.ExitUnknownSubCommand)
@@ -56,19 +120,23 @@ func main() {
 		os.Exit(cli.ExitSuccess)
 	}
 	_, _ = fmt.Fprintf(os.Stderr, "Err: %v\n", err)
-	if errors.Is(err, api.ErrUploadFailed) || errors.Is(err, api.ErrDownloadFailed) {
-		os.Exit(exitCode)
+	for _, failureErr := range failureErrs {
+		if errors.Is(err, failureErr) {
+			os.Exit(exitCode)
+		}
 	}
 	subCommandFlags.Usage()
 	os.Exit(cli.ExitGeneral)
 }
 
 func printExpectedSubCommand() {
-	_, _ = fmt.Fprintln(os.Stderr, fmt.Sprintf(`Expected one of 
-	%s upload <flags> <args> 
-	%s download <flags> <args>
-See %s upload|download -h
-`, api.ProgramName, api.ProgramName, api.ProgramName))
+	_, _ = fmt.Fprintln(os.Stderr, "Expected one of")
+	names := make([]string, 0, len(subCommands))
+	for _, sc := range subCommands {
+		_, _ = fmt.Fprintf(os.Stderr, "\t%s %s %s\n", api.ProgramName, sc.name, sc.args)
+		names = append(names, sc.name)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "See %s %s -h\n", api.ProgramName, strings.Join(names, "|"))
 }
 
 func ExecDownload(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
@@ -79,12 +147,16 @@ func ExecDownload(ctx context.Context, subCommandFlags *flag.FlagSet, args []str
 		exitCode = cli.ExitOptsParseErr
 		return
 	}
+	if subCommandFlags.Changed("shoot") && !subCommandFlags.Changed(clientcmd.RecommendedConfigPathFlag) {
+		// explicit shoot coordinates take precedence over the KUBECONFIG env-var default
+		mainOpts.KubeConfigPath = ""
+	}
 	exitCode, err = cli.ValidateMainOptsForDownload(&mainOpts, subCommandFlags.Args())
 	if err != nil {
 		return
 	}
 
-	copier, err := NewShootCopierFromOpts(mainOpts)
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
 	if err != nil {
 		if errors.Is(err, api.ErrCreateKubeClient) {
 			exitCode = cli.ExitKubeClientCreate
@@ -95,6 +167,9 @@ func ExecDownload(ctx context.Context, subCommandFlags *flag.FlagSet, args []str
 	gvrs := subCommandFlags.Args()
 	if len(gvrs) == 0 {
 		gvrs = api.DefaultGVRs
+		if mainOpts.Closure {
+			gvrs = api.DefaultClosureRootGVRs
+		}
 		slog.Warn("No gvrs specified. Assuming default.", "gvrs", gvrs)
 	}
 	gvrList, err := api.ParseGVRs(gvrs)
@@ -103,13 +178,417 @@ func ExecDownload(ctx context.Context, subCommandFlags *flag.FlagSet, args []str
 		return
 	}
 
-	err = copier.DownloadObjects(ctx, mainOpts.ObjDir, gvrList)
+	if mainOpts.Closure {
+		err = copier.DownloadClosure(ctx, mainOpts.ObjDir, gvrList, mainOpts.Selector)
+	} else {
+		err = copier.DownloadObjects(ctx, mainOpts.ObjDir, gvrList)
+	}
 	if err != nil {
 		exitCode = cli.ExitDownloadFailed
 		return
 	}
+	if mainOpts.ControlKubeConfigPath != "" {
+		err = copier.DownloadControlObjects(ctx, mainOpts.ObjDir)
+		if err != nil {
+			exitCode = cli.ExitDownloadFailed
+			return
+		}
+	}
+	if mainOpts.NodeTemplates {
+		err = copier.GenNodeTemplates(ctx, mainOpts.ObjDir)
+		if err != nil {
+			exitCode = cli.ExitDownloadFailed
+			return
+		}
+	}
+	return
+}
+func ExecWatch(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupWatchFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsCommon(&mainOpts)
+	if err != nil {
+		return
+	}
+
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
+	if err != nil {
+		if errors.Is(err, api.ErrCreateKubeClient) {
+			exitCode = cli.ExitKubeClientCreate
+		}
+		return
+	}
+
+	gvrs := subCommandFlags.Args()
+	if len(gvrs) == 0 {
+		gvrs = api.DefaultGVRs
+		slog.Warn("No gvrs specified. Assuming default.", "gvrs", gvrs)
+	}
+	gvrList, err := api.ParseGVRs(gvrs)
+	if err != nil {
+		exitCode = cli.ExitParseGVR
+		return
+	}
+
+	err = copier.WatchObjects(ctx, mainOpts.ObjDir, gvrList)
+	if err != nil {
+		exitCode = cli.ExitWatchFailed
+		return
+	}
+	return
+}
+
+func ExecReplay(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupReplayFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForReplay(&mainOpts)
+	if err != nil {
+		return
+	}
+
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
+	if err != nil {
+		if errors.Is(err, api.ErrCreateKubeClient) {
+			exitCode = cli.ExitKubeClientCreate
+		}
+		return
+	}
+	err = copier.ReplayEvents(ctx, mainOpts.ObjDir, mainOpts.Replay)
+	if err != nil {
+		exitCode = cli.ExitReplayFailed
+		return
+	}
+	return
+}
+
+func ExecCopy(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupCopyFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForCopy(&mainOpts)
+	if err != nil {
+		return
+	}
+
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
+	if err != nil {
+		if errors.Is(err, api.ErrCreateKubeClient) {
+			exitCode = cli.ExitKubeClientCreate
+		}
+		return
+	}
+
+	gvrs := subCommandFlags.Args()
+	if len(gvrs) == 0 {
+		gvrs = api.DefaultGVRs
+		slog.Warn("No gvrs specified. Assuming default.", "gvrs", gvrs)
+	}
+	gvrList, err := api.ParseGVRs(gvrs)
+	if err != nil {
+		exitCode = cli.ExitParseGVR
+		return
+	}
+
+	err = copier.CopyObjects(ctx, gvrList, mainOpts.ObjDir)
+	if err != nil {
+		exitCode = cli.ExitCopyFailed
+		return
+	}
 	return
 }
+
+func ExecCopyShoot(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupCopyShootFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForCopyShoot(&mainOpts, subCommandFlags.Args())
+	if err != nil {
+		return
+	}
+	gvrList, err := api.ParseGVRs(subCommandFlags.Args())
+	if err != nil {
+		exitCode = cli.ExitParseGVR
+		return
+	}
+
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
+	if err != nil {
+		if errors.Is(err, api.ErrCreateKubeClient) {
+			exitCode = cli.ExitKubeClientCreate
+		}
+		return
+	}
+	report, err := copier.CopyShootObjects(ctx, gvrList)
+	if err != nil {
+		exitCode = cli.ExitCopyFailed
+		return
+	}
+	err = core.WriteShootCopyReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitCopyFailed
+		err = fmt.Errorf("%w: %w", api.ErrCopyFailed, err)
+	}
+	return
+}
+
+func ExecDiff(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupDiffFlagsToOpts(subCommandFlags, &mainOpts, core.DefaultDiffIgnoreFields)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	dirs := subCommandFlags.Args()
+	exitCode, err = cli.ValidateMainOptsForDiff(&mainOpts, dirs)
+	if err != nil {
+		return
+	}
+
+	var report api.DiffReport
+	if mainOpts.KubeConfigPath != "" {
+		var copier api.ShootCopier
+		copier, err = NewShootCopierFromOpts(ctx, mainOpts)
+		if err != nil {
+			if errors.Is(err, api.ErrCreateKubeClient) {
+				exitCode = cli.ExitKubeClientCreate
+			}
+			return
+		}
+		report, err = core.DiffObjDirWithCluster(ctx, copier.GetClient(), dirs[0], mainOpts.IgnoreFields)
+	} else {
+		report, err = core.DiffObjDirs(dirs[0], dirs[1], mainOpts.IgnoreFields)
+	}
+	if err != nil {
+		exitCode = cli.ExitDiffFailed
+		return
+	}
+	err = core.WriteDiffReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitDiffFailed
+		err = fmt.Errorf("%w: %w", api.ErrDiffFailed, err)
+	}
+	return
+}
+
+func ExecInspect(subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupInspectFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForInspect(&mainOpts)
+	if err != nil {
+		return
+	}
+	report, err := core.InspectObjDir(mainOpts.ObjDir, mainOpts.TopN)
+	if err != nil {
+		exitCode = cli.ExitInspectFailed
+		return
+	}
+	err = core.WriteInspectReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitInspectFailed
+		err = fmt.Errorf("%w: %w", api.ErrInspectFailed, err)
+	}
+	return
+}
+
+func ExecReport(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupReportPlacementFlagsToOpts(subCommandFlags, &mainOpts)
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
+		subCommandFlags.Usage()
+		err = flag.ErrHelp
+		return
+	}
+	if len(args) == 0 || args[0] != "placement" {
+		exitCode = cli.ExitMissingArgs
+		err = fmt.Errorf("%w: expected one of: placement", api.ErrMissingReportType)
+		return
+	}
+	err = subCommandFlags.Parse(args[1:])
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForReportPlacement(&mainOpts)
+	if err != nil {
+		return
+	}
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
+	if err != nil {
+		if errors.Is(err, api.ErrCreateKubeClient) {
+			exitCode = cli.ExitKubeClientCreate
+		}
+		return
+	}
+	report, err := core.PlacementReportFromCluster(ctx, copier.GetClient(), mainOpts.ObjDir)
+	if err != nil {
+		exitCode = cli.ExitReportFailed
+		return
+	}
+	err = core.WritePlacementReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitReportFailed
+		err = fmt.Errorf("%w: %w", api.ErrReportFailed, err)
+	}
+	return
+}
+
+func ExecSimulate(subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupSimulateFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForSimulate(&mainOpts)
+	if err != nil {
+		return
+	}
+	var schedulerConfig []byte
+	if mainOpts.KubeSchedulerConfigPath != "" {
+		schedulerConfig, err = os.ReadFile(mainOpts.KubeSchedulerConfigPath)
+	} else {
+		schedulerConfig, err = core.RenderKubeSchedulerConfiguration("", 0, 0, mainOpts.SchedulerConfig)
+	}
+	if err != nil {
+		exitCode = cli.ExitSimulateFailed
+		err = fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+		return
+	}
+	report, err := core.SimulateObjDir(mainOpts.ObjDir, schedulerConfig, mainOpts.SchedulerName)
+	if err != nil {
+		exitCode = cli.ExitSimulateFailed
+		return
+	}
+	err = core.WriteSimulationReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitSimulateFailed
+		err = fmt.Errorf("%w: %w", api.ErrSimulateFailed, err)
+	}
+	return
+}
+
+func ExecValidate(subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupValidateFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForValidate(&mainOpts)
+	if err != nil {
+		return
+	}
+	report, err := core.ValidateObjDir(mainOpts.ObjDir)
+	if err != nil {
+		exitCode = cli.ExitValidateFailed
+		return
+	}
+	err = core.WriteValidationReport(os.Stdout, report, mainOpts.OutputFormat)
+	if err != nil {
+		exitCode = cli.ExitValidateFailed
+		err = fmt.Errorf("%w: %w", api.ErrValidateFailed, err)
+		return
+	}
+	if report.NumErrors > 0 {
+		exitCode = cli.ExitValidateFailed
+		err = fmt.Errorf("%w: %d errors in %q", api.ErrValidateFailed, report.NumErrors, mainOpts.ObjDir)
+	}
+	return
+}
+
+func ExecGenKubeConfig(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupGenKubeConfigFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForGenKubeConfig(&mainOpts)
+	if err != nil {
+		return
+	}
+	gardenClient, err := clientutil.CreateDynamicClient(mainOpts.GardenKubeConfigPath)
+	if err != nil {
+		exitCode = cli.ExitKubeClientCreate
+		err = fmt.Errorf("%w: cannot create garden client from %q: %w", api.ErrCreateGardenClient, mainOpts.GardenKubeConfigPath, err)
+		return
+	}
+	accessLevels := []core.KubeConfigAccess{core.ViewerAccess}
+	if mainOpts.AdminKubeConfig {
+		accessLevels = append(accessLevels, core.AdminAccess)
+	}
+	for _, access := range accessLevels {
+		var path string
+		path, err = core.WriteShootKubeConfig(ctx, gardenClient, mainOpts.Shoot, access, mainOpts.KubeConfigExpiration, mainOpts.GenDir)
+		if err != nil {
+			exitCode = cli.ExitGenKubeConfigFailed
+			return
+		}
+		fmt.Println(path)
+	}
+	return
+}
+
+func ExecGenSchedulerConfig(subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
+	var mainOpts cli.MainOpts
+	cli.SetupGenSchedulerConfigFlagsToOpts(subCommandFlags, &mainOpts)
+	err = subCommandFlags.Parse(args)
+	if err != nil {
+		exitCode = cli.ExitOptsParseErr
+		return
+	}
+	exitCode, err = cli.ValidateMainOptsForGenSchedulerConfig(&mainOpts)
+	if err != nil {
+		return
+	}
+	qps, burst := mainOpts.ClientRateLimit()
+	if mainOpts.KubeSchedulerConfigPath != "" {
+		err = core.GenKubeSchedulerConfiguration(mainOpts.KubeSchedulerConfigPath, mainOpts.KubeConfigPath, qps, burst, mainOpts.SchedulerConfig)
+		if err != nil {
+			exitCode = cli.ExitGenSchedulerConfigFailed
+		}
+		return
+	}
+	data, err := core.RenderKubeSchedulerConfiguration(mainOpts.KubeConfigPath, qps, burst, mainOpts.SchedulerConfig)
+	if err != nil {
+		exitCode = cli.ExitGenSchedulerConfigFailed
+		return
+	}
+	_, err = os.Stdout.Write(data)
+	if err != nil {
+		exitCode = cli.ExitGenSchedulerConfigFailed
+		err = fmt.Errorf("%w: %w", api.ErrGenSchedulerConfig, err)
+	}
+	return
+}
+
 func ExecUpload(ctx context.Context, subCommandFlags *flag.FlagSet, args []string) (exitCode int, err error) {
 	var mainOpts cli.MainOpts
 	cli.SetupUploadFlagsToOpts(subCommandFlags, &mainOpts)
@@ -123,30 +602,67 @@ func ExecUpload(ctx context.Context, subCommandFlags *flag.FlagSet, args []strin
 		return
 	}
 
-	copier, err := NewShootCopierFromOpts(mainOpts)
+	copier, err := NewShootCopierFromOpts(ctx, mainOpts)
 	if err != nil {
 		if errors.Is(err, api.ErrCreateKubeClient) {
 			exitCode = cli.ExitKubeClientCreate
 		}
 		return
 	}
-	err = copier.UploadObjects(ctx, mainOpts.ObjDir)
+	uploadReport, err := copier.UploadObjectsWithReport(ctx, mainOpts.ObjDir)
+	if uploadReport.NumObjs > 0 {
+		_ = core.WriteUploadReport(os.Stdout, uploadReport, api.OutputText)
+	}
 	if err != nil {
-		if errors.Is(err, api.ErrDownloadFailed) {
-			exitCode = cli.ExitDownloadFailed
+		if errors.Is(err, api.ErrUploadFailed) {
+			exitCode = cli.ExitUploadFailed
 		}
 		return
 	}
-	err = core.GenKubeSchedulerConfiguration(mainOpts.KubeSchedulerConfigPath, mainOpts.KubeConfigPath, mainOpts.PoolSize)
-	if err != nil {
-		return
+	if mainOpts.GenSchedulerConfig {
+		qps, burst := mainOpts.ClientRateLimit()
+		err = core.GenKubeSchedulerConfiguration(mainOpts.KubeSchedulerConfigPath, mainOpts.KubeConfigPath, qps, burst, mainOpts.SchedulerConfig)
+		if err != nil {
+			exitCode = cli.ExitGenSchedulerConfigFailed
+			return
+		}
+	}
+	if mainOpts.KWOK {
+		err = core.GenKWOKConfiguration(mainOpts.KWOKConfigPath)
+		if err != nil {
+			exitCode = cli.ExitGenKWOKConfigFailed
+			return
+		}
+	}
+	var lifecycleErrCh chan error
+	if mainOpts.SimulateLifecycle {
+		lifecycleErrCh = make(chan error, 1)
+		go func() {
+			lifecycleErrCh <- copier.SimulateLifecycle(ctx, mainOpts.LifecycleInterval)
+		}()
+	}
+	if mainOpts.WaitScheduled > 0 {
+		var report api.SchedulingReport
+		report, err = copier.WaitScheduled(ctx, mainOpts.ObjDir, mainOpts.WaitScheduled)
+		if err != nil {
+			exitCode = cli.ExitWaitScheduledFailed
+			return
+		}
+		err = core.WriteSchedulingReport(os.Stdout, report, api.OutputText)
+		if err != nil {
+			exitCode = cli.ExitWaitScheduledFailed
+			err = fmt.Errorf("%w: %w", api.ErrWaitScheduled, err)
+			return
+		}
+	}
+	if lifecycleErrCh != nil {
+		err = <-lifecycleErrCh
+		if err != nil {
+			exitCode = cli.ExitSimulateLifecycleFailed
+		}
 	}
-	//err = core.GenerateSchedulerProfile(mainOpts.KubeConfigPath, "/tmp/-ksched-config.yaml")
-	//if err != nil {
-	//	return
-	//}
 	return
 }
-func NewShootCopierFromOpts(opts cli.MainOpts) (copier api.ShootCopier, err error) {
-	return core.NewShootCopierFromConfig(opts.CopierConfig)
+func NewShootCopierFromOpts(ctx context.Context, opts cli.MainOpts) (copier api.ShootCopier, err error) {
+	return core.NewShootCopierFromConfigWithContext(ctx, opts.CopierConfig)
 }